@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"strings"
+	"sync"
+)
+
+var aliases struct {
+	sync.RWMutex
+	names map[string]string
+}
+
+// RegisterAlias makes the parser treat the directive alias as canonical, e.g.
+// RegisterAlias("x-vendor-maxage", HeaderMaxAge) while migrating away from a
+// vendor directive. Both names are matched case-insensitively. Aliases are
+// resolved before the directive is matched against the known directives, so
+// an alias of a standard directive sets the corresponding struct field.
+//
+// When a header carries both the alias and the canonical directive, they are
+// the same directive to the parser: the last one in the header wins.
+//
+// Aliases are global to the package; register them during initialization.
+// Registering an alias to "" removes it.
+func RegisterAlias(alias, canonical string) {
+	alias, canonical = strings.ToLower(alias), strings.ToLower(canonical)
+
+	aliases.Lock()
+	defer aliases.Unlock()
+
+	if canonical == "" {
+		delete(aliases.names, alias)
+		return
+	}
+	if aliases.names == nil {
+		aliases.names = make(map[string]string)
+	}
+	aliases.names[alias] = canonical
+}
+
+// resolveAlias returns the canonical name registered for name, or name.
+func resolveAlias(name string) string {
+	aliases.RLock()
+	defer aliases.RUnlock()
+
+	if canonical, ok := aliases.names[name]; ok {
+		return canonical
+	}
+	return name
+}
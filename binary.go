@@ -0,0 +1,206 @@
+package cache
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+)
+
+var (
+	ErrInvalidBinary = errors.New("invalid binary encoding of cache directive")
+)
+
+// binaryVersion is the first byte of every binary encoding.
+const binaryVersion = 1
+
+// Bits of the boolean directive bitfield.
+const (
+	bitMustRevalidate = 1 << iota
+	bitNoCache
+	bitNoStore
+	bitNoTransform
+	bitPublic
+	bitPrivate
+	bitProxyRevalidate
+	bitImmutable
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler with a compact form meant
+// for storing directive metadata next to cache entries:
+//
+//	version      byte (1)
+//	flags        uvarint bitfield of the boolean directives
+//	max-age, s-maxage, stale-if-error, stale-while-revalidate
+//	             varint each, -1 when absent
+//	no-cache     uvarint count, then uvarint length-prefixed field names
+//	private      same as no-cache
+//	extensions   uvarint count, then uvarint length-prefixed entries
+//
+// Field names are written sorted, so Equal directives encode identically
+// as long as their extensions are in the same order. NoCacheRaw and
+// PrivateRaw are not encoded.
+func (directive *ResponseCacheDirective) MarshalBinary() ([]byte, error) {
+	var flags uint64
+	if directive.MustRevalidate {
+		flags |= bitMustRevalidate
+	}
+	if directive.NoCachePresent {
+		flags |= bitNoCache
+	}
+	if directive.NoStore {
+		flags |= bitNoStore
+	}
+	if directive.NoTransform {
+		flags |= bitNoTransform
+	}
+	if directive.Public {
+		flags |= bitPublic
+	}
+	if directive.PrivatePresent {
+		flags |= bitPrivate
+	}
+	if directive.ProxyRevalidate {
+		flags |= bitProxyRevalidate
+	}
+	if directive.Immutable {
+		flags |= bitImmutable
+	}
+
+	buf := []byte{binaryVersion}
+	buf = binary.AppendUvarint(buf, flags)
+	for _, delta := range []int32{directive.maxAge(), directive.sMaxAge(), directive.staleIfError(), directive.staleWhileRevalidate()} {
+		buf = binary.AppendVarint(buf, int64(delta))
+	}
+	buf = appendBinaryStrings(buf, sortedFields(directive.NoCache))
+	buf = appendBinaryStrings(buf, sortedFields(directive.Private))
+	buf = appendBinaryStrings(buf, directive.Extensions)
+	return buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, decoding the form
+// written by MarshalBinary into the directive.
+func (directive *ResponseCacheDirective) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != binaryVersion {
+		return ErrInvalidBinary
+	}
+	r := binaryReader{data: data[1:]}
+
+	flags := r.uvarint()
+	var deltas [4]int32
+	for i := range deltas {
+		delta := r.varint()
+		if delta < -1 || delta > 1<<31-1 {
+			return ErrInvalidBinary
+		}
+		deltas[i] = int32(delta)
+	}
+	noCache := r.strings()
+	private := r.strings()
+	extensions := r.strings()
+	if r.err || len(r.data) != 0 {
+		return ErrInvalidBinary
+	}
+
+	*directive = ResponseCacheDirective{
+		MustRevalidate:  flags&bitMustRevalidate != 0,
+		NoCachePresent:  flags&bitNoCache != 0,
+		NoStore:         flags&bitNoStore != 0,
+		NoTransform:     flags&bitNoTransform != 0,
+		Public:          flags&bitPublic != 0,
+		PrivatePresent:  flags&bitPrivate != 0,
+		ProxyRevalidate: flags&bitProxyRevalidate != 0,
+		Immutable:       flags&bitImmutable != 0,
+		NoCache:         fieldSet(noCache),
+		Private:         fieldSet(private),
+		Extensions:      extensions,
+	}
+	directive.MaxAge, directive.MaxAgePresent = decodeDeltaSeconds(deltas[0])
+	directive.SMaxAge, directive.SMaxAgePresent = decodeDeltaSeconds(deltas[1])
+	directive.StaleIfError, directive.StaleIfErrorPresent = decodeDeltaSeconds(deltas[2])
+	directive.StaleWhileRevalidate, directive.StaleWhileRevalidatePresent = decodeDeltaSeconds(deltas[3])
+	return nil
+}
+
+// decodeDeltaSeconds splits an encoded delta-seconds value into the value and
+// presence flag of its field.
+func decodeDeltaSeconds(delta int32) (int32, bool) {
+	if delta < 0 {
+		return 0, false
+	}
+	return delta, true
+}
+
+func sortedFields(fields map[string]bool) []string {
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func fieldSet(names []string) map[string]bool {
+	if len(names) == 0 {
+		return nil
+	}
+	fields := make(map[string]bool, len(names))
+	for _, name := range names {
+		fields[name] = true
+	}
+	return fields
+}
+
+func appendBinaryStrings(buf []byte, list []string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(list)))
+	for _, s := range list {
+		buf = binary.AppendUvarint(buf, uint64(len(s)))
+		buf = append(buf, s...)
+	}
+	return buf
+}
+
+// binaryReader decodes MarshalBinary output, remembering the first error.
+type binaryReader struct {
+	data []byte
+	err  bool
+}
+
+func (r *binaryReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.err = true
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *binaryReader) varint() int64 {
+	v, n := binary.Varint(r.data)
+	if n <= 0 {
+		r.err = true
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *binaryReader) strings() []string {
+	count := r.uvarint()
+	if r.err || count > uint64(len(r.data)) {
+		r.err = true
+		return nil
+	}
+
+	var list []string
+	for i := uint64(0); i < count; i++ {
+		size := r.uvarint()
+		if r.err || size > uint64(len(r.data)) {
+			r.err = true
+			return nil
+		}
+		list = append(list, string(r.data[:size]))
+		r.data = r.data[size:]
+	}
+	return list
+}
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	values := []string{"", `no-cache="X-Id, Set-Cookie", private, max-age=0, ext="a b", ext`}
+	for _, tt := range responseCorpus {
+		values = append(values, tt.value)
+	}
+	for _, value := range values {
+		directive, err := NewResponseCacheDirective(value)
+		if err != nil {
+			t.Errorf("NewResponseCacheDirective(%q): %v", value, err)
+			continue
+		}
+		data, err := directive.MarshalBinary()
+		if err != nil {
+			t.Errorf("%q: MarshalBinary: %v", value, err)
+			continue
+		}
+		var decoded ResponseCacheDirective
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			t.Errorf("%q: UnmarshalBinary(%x): %v", value, data, err)
+			continue
+		}
+		if !decoded.Equal(directive) || decoded.String() != directive.String() {
+			t.Errorf("%q: round trip = %q, want %q", value, &decoded, directive)
+		}
+	}
+}
+
+func TestUnmarshalBinaryInvalid(t *testing.T) {
+	directive, err := NewResponseCacheDirective(`public, max-age=60, no-cache="X-Id", ext=1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid, err := directive.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every field is required, so any truncation is an error.
+	for n := 0; n < len(valid); n++ {
+		var decoded ResponseCacheDirective
+		if err := decoded.UnmarshalBinary(valid[:n]); !errors.Is(err, ErrInvalidBinary) {
+			t.Errorf("UnmarshalBinary(%x) = %v, want ErrInvalidBinary", valid[:n], err)
+		}
+	}
+
+	header := []byte{binaryVersion, 0}
+	for i := 0; i < 4; i++ {
+		header = binary.AppendVarint(header, -1)
+	}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"bad version", append([]byte{binaryVersion + 1}, valid[1:]...)},
+		{"trailing bytes", append(append([]byte(nil), valid...), 0)},
+		{"delta below -1", binary.AppendVarint([]byte{binaryVersion, 0}, -2)},
+		{"delta above MaxInt32", binary.AppendVarint([]byte{binaryVersion, 0}, 1<<31)},
+		{"oversize count", binary.AppendUvarint(append([]byte(nil), header...), 1<<62)},
+		{"oversize length", binary.AppendUvarint(binary.AppendUvarint(append([]byte(nil), header...), 1), 1<<62)},
+		{"overlong varint", append([]byte{binaryVersion}, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01)},
+	}
+	for _, tt := range tests {
+		var decoded ResponseCacheDirective
+		if err := decoded.UnmarshalBinary(tt.data); !errors.Is(err, ErrInvalidBinary) {
+			t.Errorf("%s: UnmarshalBinary(%x) = %v, want ErrInvalidBinary", tt.name, tt.data, err)
+		}
+	}
+}
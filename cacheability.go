@@ -0,0 +1,106 @@
+package cache
+
+// Cacheability summarizes the caching posture of a response for a cache type,
+// see (*ResponseCacheDirective).Cacheability.
+type Cacheability int
+
+const (
+	// NotStorable means the cache may not store the response, see
+	// Uncacheable.
+	NotStorable Cacheability = iota
+
+	// StorableRevalidateAlways means the response may be stored but every
+	// reuse requires revalidation: bare no-cache, or an explicit lifetime of
+	// zero such as max-age=0.
+	StorableRevalidateAlways
+
+	// StorableImmutable means the response is fresh for a positive explicit
+	// lifetime and carries immutable, so it need not even be revalidated on
+	// reload (RFC 8246).
+	StorableImmutable
+
+	// StorableWithFreshness means the response is fresh for a positive
+	// explicit lifetime.
+	StorableWithFreshness
+
+	// StorableHeuristic means the response may be stored but has no explicit
+	// lifetime in Cache-Control: its freshness depends on Expires or on a
+	// heuristic (RFC 9111 Section 4.2.2).
+	StorableHeuristic
+)
+
+func (c Cacheability) String() string {
+	switch c {
+	case NotStorable:
+		return "not-storable"
+	case StorableRevalidateAlways:
+		return "storable-revalidate-always"
+	case StorableImmutable:
+		return "storable-immutable"
+	case StorableWithFreshness:
+		return "storable-with-freshness"
+	case StorableHeuristic:
+		return "storable-heuristic"
+	}
+	return "unknown"
+}
+
+// Cacheability classifies the response for a cache of the given type. The
+// first matching value wins, in the order they are declared: NotStorable,
+// StorableRevalidateAlways, StorableImmutable, StorableWithFreshness and
+// StorableHeuristic. So no-store beats everything, and `no-cache, immutable`
+// still revalidates always.
+//
+// Only the directives are considered: storability conditions that depend on
+// the status code or the request, see Storable, are left to the caller.
+func (directive *ResponseCacheDirective) Cacheability(shared bool) Cacheability {
+	if directive.Uncacheable(shared) {
+		return NotStorable
+	}
+
+	lifetime := directive.lifetime(shared)
+	switch {
+	case directive.NoCachePresent && len(directive.NoCache) == 0, lifetime == 0:
+		return StorableRevalidateAlways
+	case lifetime < 0:
+		return StorableHeuristic
+	case directive.Immutable:
+		return StorableImmutable
+	}
+	return StorableWithFreshness
+}
+
+// IgnoredDirectives returns the directives present in the response that do not
+// apply to a cache of the given type, in the order of String, to help tools
+// explain cache behaviour:
+//
+//   - shared caches ignore max-age when s-maxage is present, which overrides
+//     it for them (RFC 9111 Section 5.2.2.10), and immutable, which only
+//     changes how user agents handle a reload (RFC 8246 Section 2);
+//   - private caches ignore s-maxage (RFC 9111 Section 5.2.2.10) and
+//     proxy-revalidate (RFC 9111 Section 5.2.2.8), which only apply to
+//     shared caches.
+//
+// private is never reported: it forbids shared caches from storing the
+// response, or the listed fields, rather than being ignored by them, see
+// Uncacheable and PrivateFieldsToStrip.
+func (directive *ResponseCacheDirective) IgnoredDirectives(shared bool) []string {
+	var ignored []string
+	if shared {
+		if directive.MaxAgePresent && directive.SMaxAgePresent {
+			ignored = append(ignored, HeaderMaxAge)
+		}
+		if directive.Immutable {
+			ignored = append(ignored, HeaderImmutable)
+		}
+		return ignored
+	}
+
+	if directive.SMaxAgePresent {
+		ignored = append(ignored, HeaderSMaxAge)
+	}
+	if directive.ProxyRevalidate {
+		ignored = append(ignored, HeaderProxyRevalidate)
+	}
+	return ignored
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCacheability(t *testing.T) {
+	tests := []struct {
+		header  string
+		shared  Cacheability
+		private Cacheability
+	}{
+		{"no-store", NotStorable, NotStorable},
+		{"no-store, max-age=60, immutable", NotStorable, NotStorable},
+		{"private, max-age=60", NotStorable, StorableWithFreshness},
+		{`private="Set-Cookie", max-age=60`, StorableWithFreshness, StorableWithFreshness},
+		{"no-cache", StorableRevalidateAlways, StorableRevalidateAlways},
+		{"no-cache, max-age=60, immutable", StorableRevalidateAlways, StorableRevalidateAlways},
+		{`no-cache="Set-Cookie", max-age=60`, StorableWithFreshness, StorableWithFreshness},
+		{"max-age=0", StorableRevalidateAlways, StorableRevalidateAlways},
+		{"max-age=60, s-maxage=0", StorableRevalidateAlways, StorableWithFreshness},
+		{"s-maxage=60", StorableWithFreshness, StorableHeuristic},
+		{"max-age=60, immutable", StorableImmutable, StorableImmutable},
+		{"max-age=0, immutable", StorableRevalidateAlways, StorableRevalidateAlways},
+		{"max-age=60, stale-while-revalidate=30, stale-if-error=300", StorableWithFreshness, StorableWithFreshness},
+		{"public", StorableHeuristic, StorableHeuristic},
+		{"", StorableHeuristic, StorableHeuristic},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := directive.Cacheability(true); got != tt.shared {
+			t.Errorf("%q: Cacheability(true) = %v, want %v", tt.header, got, tt.shared)
+		}
+		if got := directive.Cacheability(false); got != tt.private {
+			t.Errorf("%q: Cacheability(false) = %v, want %v", tt.header, got, tt.private)
+		}
+	}
+}
+
+func TestCacheabilityString(t *testing.T) {
+	tests := []struct {
+		c    Cacheability
+		want string
+	}{
+		{NotStorable, "not-storable"},
+		{StorableRevalidateAlways, "storable-revalidate-always"},
+		{StorableImmutable, "storable-immutable"},
+		{StorableWithFreshness, "storable-with-freshness"},
+		{StorableHeuristic, "storable-heuristic"},
+		{Cacheability(-1), "unknown"},
+	}
+	for _, tt := range tests {
+		if got := tt.c.String(); got != tt.want {
+			t.Errorf("Cacheability(%d).String() = %q, want %q", int(tt.c), got, tt.want)
+		}
+	}
+}
+
+func TestIgnoredDirectives(t *testing.T) {
+	tests := []struct {
+		header  string
+		shared  []string
+		private []string
+	}{
+		{"public, max-age=60", nil, nil},
+		{"max-age=60, s-maxage=120", []string{HeaderMaxAge}, []string{HeaderSMaxAge}},
+		{"max-age=60, immutable", []string{HeaderImmutable}, nil},
+		{"max-age=60, proxy-revalidate", nil, []string{HeaderProxyRevalidate}},
+		{"private, max-age=60, s-maxage=0, proxy-revalidate, immutable", []string{HeaderMaxAge, HeaderImmutable}, []string{HeaderSMaxAge, HeaderProxyRevalidate}},
+		{"s-maxage=60", nil, []string{HeaderSMaxAge}},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := directive.IgnoredDirectives(true); !reflect.DeepEqual(got, tt.shared) {
+			t.Errorf("%q: IgnoredDirectives(true) = %q, want %q", tt.header, got, tt.shared)
+		}
+		if got := directive.IgnoredDirectives(false); !reflect.DeepEqual(got, tt.private) {
+			t.Errorf("%q: IgnoredDirectives(false) = %q, want %q", tt.header, got, tt.private)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package cache
+
+// ClassifyDirectives sorts the directives of a Cache-Control value by the
+// direction RFC 9111, RFC 5861 and RFC 8246 define them for, to diagnose a
+// header set on the wrong side of an exchange, such as public in a request:
+//
+//   - requestOnly: max-stale, min-fresh and only-if-cached;
+//   - responseOnly: public, private, s-maxage, must-revalidate,
+//     proxy-revalidate, immutable and stale-while-revalidate;
+//   - shared: max-age, no-cache, no-store, no-transform and stale-if-error;
+//   - unknown: anything else.
+//
+// Names are lower-cased, with registered aliases resolved, and listed in the
+// order they appear, once per occurrence. Values are ignored. The value is
+// only scanned, so a directive with a malformed value, like `max-age=abc`, is
+// still classified; a value that cannot be scanned returns the scanner error
+// and nil slices.
+func ClassifyDirectives(value string) (requestOnly, responseOnly, shared, unknown []string, err error) {
+	s := NewDirectiveScanner(value)
+	for s.Scan() {
+		name, _, _ := s.Directive()
+		name = resolveAlias(name)
+		switch name {
+		case HeaderMaxStale, HeaderMinFresh, HeaderOnlyIfCached:
+			requestOnly = append(requestOnly, name)
+		case HeaderPublic, HeaderPrivate, HeaderSMaxAge, HeaderMustRevalidate,
+			HeaderProxyRevalidate, HeaderImmutable, HeaderStaleWhileRevalidate:
+			responseOnly = append(responseOnly, name)
+		case HeaderMaxAge, HeaderNoCache, HeaderNoStore, HeaderNoTransform, HeaderStaleIfError:
+			shared = append(shared, name)
+		default:
+			unknown = append(unknown, name)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return requestOnly, responseOnly, shared, unknown, nil
+}
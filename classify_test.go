@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestClassifyDirectives(t *testing.T) {
+	tests := []struct {
+		value        string
+		requestOnly  []string
+		responseOnly []string
+		shared       []string
+		unknown      []string
+	}{
+		{"", nil, nil, nil, nil},
+		{"max-stale=10, min-fresh=5, only-if-cached", []string{HeaderMaxStale, HeaderMinFresh, HeaderOnlyIfCached}, nil, nil, nil},
+		{
+			"Public, private, s-maxage=1, must-revalidate, proxy-revalidate, immutable, stale-while-revalidate=1",
+			nil,
+			[]string{HeaderPublic, HeaderPrivate, HeaderSMaxAge, HeaderMustRevalidate, HeaderProxyRevalidate, HeaderImmutable, HeaderStaleWhileRevalidate},
+			nil, nil,
+		},
+		{"max-age=abc, NO-CACHE, no-store, no-transform, stale-if-error=1", nil, nil, []string{HeaderMaxAge, HeaderNoCache, HeaderNoStore, HeaderNoTransform, HeaderStaleIfError}, nil},
+		{"public, max-age=60, max-age=60, ext=1, Other", nil, []string{HeaderPublic}, []string{HeaderMaxAge, HeaderMaxAge}, []string{"ext", "other"}},
+	}
+	for _, tt := range tests {
+		requestOnly, responseOnly, shared, unknown, err := ClassifyDirectives(tt.value)
+		if err != nil {
+			t.Errorf("ClassifyDirectives(%q): %v", tt.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(requestOnly, tt.requestOnly) || !reflect.DeepEqual(responseOnly, tt.responseOnly) ||
+			!reflect.DeepEqual(shared, tt.shared) || !reflect.DeepEqual(unknown, tt.unknown) {
+			t.Errorf("ClassifyDirectives(%q) = %q, %q, %q, %q, want %q, %q, %q, %q", tt.value,
+				requestOnly, responseOnly, shared, unknown, tt.requestOnly, tt.responseOnly, tt.shared, tt.unknown)
+		}
+	}
+
+	requestOnly, responseOnly, shared, unknown, err := ClassifyDirectives(`public, ext="unterminated`)
+	if !errors.Is(err, ErrMissingClosingQuote) || requestOnly != nil || responseOnly != nil || shared != nil || unknown != nil {
+		t.Errorf("ClassifyDirectives(unterminated) = %q, %q, %q, %q, %v, want nil slices and ErrMissingClosingQuote",
+			requestOnly, responseOnly, shared, unknown, err)
+	}
+}
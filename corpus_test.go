@@ -0,0 +1,108 @@
+package cache
+
+import "testing"
+
+// responseCorpus holds Cache-Control values seen in responses from CDNs and
+// object stores.
+var responseCorpus = []struct {
+	name  string
+	value string
+}{
+	{"cloudflare static", "public, max-age=14400"},
+	{"cloudflare dynamic", "private, max-age=0, no-store, no-cache, must-revalidate, post-check=0, pre-check=0"},
+	{"cloudflare pages", "public, max-age=0, must-revalidate"},
+	{"cloudflare immutable", "public, max-age=31536000, immutable"},
+	{"fastly surrogate", "max-age=0, s-maxage=86400, stale-while-revalidate=60, stale-if-error=86400"},
+	{"fastly private", `private, no-cache="Set-Cookie"`},
+	{"akamai no-store", "no-store, no-cache, must-revalidate, max-age=0"},
+	{"akamai edge", "max-age=300, public, proxy-revalidate"},
+	{"s3 default", "max-age=86400"},
+	{"s3 no-transform", "public, no-transform, max-age=604800"},
+	{"nginx expires", "max-age=3600, public"},
+	{"unquoted field list", "no-cache=Set-Cookie,max-age=60"},
+	{"unquoted field list with fields", "private=Set-Cookie,Authorization,max-age=60"},
+	{"mixed whitespace", "public,\tmax-age=60 ,  s-maxage=120\t, must-revalidate"},
+	{"upper case", "Public, Max-Age=60"},
+	{"trailing comma", "max-age=60, public,"},
+	{"trailing equals extension", "max-age=60, ext="},
+	{"empty members", ", ,max-age=60,, public"},
+}
+
+// requestCorpus holds Cache-Control values sent by browsers and HTTP clients.
+var requestCorpus = []struct {
+	name  string
+	value string
+}{
+	{"reload", "no-cache"},
+	{"hard reload", "no-cache, no-store"},
+	{"revalidate", "max-age=0"},
+	{"bare max-stale", "max-stale"},
+	{"bare max-stale with max-age", "max-age=60, max-stale"},
+	{"offline", "only-if-cached, max-stale=86400"},
+	{"mixed whitespace", "max-age=0 ,\tmin-fresh=10"},
+}
+
+func TestResponseCorpus(t *testing.T) {
+	for _, tt := range responseCorpus {
+		t.Run(tt.name, func(t *testing.T) {
+			directive, err := NewResponseCacheDirective(tt.value)
+			if err != nil {
+				t.Fatalf("NewResponseCacheDirective(%q): %v", tt.value, err)
+			}
+			again, err := NewResponseCacheDirective(directive.String())
+			if err != nil {
+				t.Fatalf("NewResponseCacheDirective(%q): %v", directive.String(), err)
+			}
+			if !again.Equal(directive) {
+				t.Errorf("%q round-trips to %q", tt.value, again.String())
+			}
+		})
+	}
+}
+
+func TestRequestCorpus(t *testing.T) {
+	for _, tt := range requestCorpus {
+		t.Run(tt.name, func(t *testing.T) {
+			directive, err := NewRequestCacheDirective(tt.value)
+			if err != nil {
+				t.Fatalf("NewRequestCacheDirective(%q): %v", tt.value, err)
+			}
+			again, err := NewRequestCacheDirective(directive.String())
+			if err != nil {
+				t.Fatalf("NewRequestCacheDirective(%q): %v", directive.String(), err)
+			}
+			if !again.Equal(directive) {
+				t.Errorf("%q round-trips to %q", tt.value, again.String())
+			}
+		})
+	}
+}
+
+func TestUnquotedFieldListStopsAtDirective(t *testing.T) {
+	directive, err := NewResponseCacheDirective("no-cache=Set-Cookie,max-age=60")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if directive.MaxAge != 60 {
+		t.Errorf("MaxAge = %d, want 60", directive.MaxAge)
+	}
+	if len(directive.NoCache) != 1 || !directive.NoCache["Set-Cookie"] {
+		t.Errorf("NoCache = %v, want only Set-Cookie", directive.NoCache)
+	}
+	if len(directive.Extensions) != 0 {
+		t.Errorf("Extensions = %q, want none", directive.Extensions)
+	}
+}
+
+func TestBareMaxStale(t *testing.T) {
+	directive, err := NewRequestCacheDirective("max-stale")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !directive.MaxStaleAny || directive.MaxStale != -1 {
+		t.Errorf("MaxStaleAny, MaxStale = %v, %d, want true, -1", directive.MaxStaleAny, directive.MaxStale)
+	}
+	if got := directive.String(); got != "max-stale" {
+		t.Errorf("String() = %q, want %q", got, "max-stale")
+	}
+}
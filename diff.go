@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"sort"
+	"strings"
+)
+
+// FieldDiff describes one directive that differs between two response
+// directives.
+type FieldDiff struct {
+	// Directive is the directive name, or "extensions" for cache-extensions.
+	Directive string
+
+	// Old and New are the serialized directive, e.g. `max-age=60`, or "" when
+	// it is absent. They are empty for extensions.
+	Old, New string
+
+	// Added and Removed list the field names, for no-cache and private, or the
+	// extensions that only appear in b or in a respectively.
+	Added, Removed []string
+}
+
+func (d FieldDiff) String() string {
+	if d.Directive == "extensions" {
+		return "extensions: +" + joinList(d.Added) + " -" + joinList(d.Removed)
+	}
+	return d.Directive + ": " + quoteOrNone(d.Old) + " -> " + quoteOrNone(d.New)
+}
+
+// Diff reports the directives that differ from a to b, in serialization
+// order, followed by the extensions if they differ. Extensions are compared as
+// a multiset: reordering them is not a change.
+func Diff(a, b *ResponseCacheDirective) []FieldDiff {
+	var diffs []FieldDiff
+	for _, name := range responseDirectives {
+		from, to := a.fragment(name), b.fragment(name)
+		if from == to {
+			continue
+		}
+
+		diff := FieldDiff{Directive: name, Old: from, New: to}
+		switch name {
+		case HeaderNoCache:
+			diff.Added, diff.Removed = diffFields(a.NoCache, b.NoCache)
+		case HeaderPrivate:
+			diff.Added, diff.Removed = diffFields(a.Private, b.Private)
+		}
+		diffs = append(diffs, diff)
+	}
+
+	added, removed := diffMultiset(a.Extensions, b.Extensions)
+	if len(added) > 0 || len(removed) > 0 {
+		diffs = append(diffs, FieldDiff{Directive: "extensions", Added: added, Removed: removed})
+	}
+	return diffs
+}
+
+func diffFields(a, b map[string]bool) (added, removed []string) {
+	for field := range b {
+		if !a[field] {
+			added = append(added, field)
+		}
+	}
+	for field := range a {
+		if !b[field] {
+			removed = append(removed, field)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+func diffMultiset(a, b []string) (added, removed []string) {
+	counts := make(map[string]int, len(a))
+	for _, s := range a {
+		counts[s]++
+	}
+	for _, s := range b {
+		if counts[s] > 0 {
+			counts[s]--
+			continue
+		}
+		added = append(added, s)
+	}
+	for _, s := range a {
+		if counts[s] > 0 {
+			counts[s]--
+			removed = append(removed, s)
+		}
+	}
+	return added, removed
+}
+
+func joinList(list []string) string {
+	return "[" + strings.Join(list, ", ") + "]"
+}
+
+func quoteOrNone(s string) string {
+	if s == "" {
+		return "(absent)"
+	}
+	return s
+}
@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want []FieldDiff
+	}{
+		{"public, max-age=60", "max-age=60, public", nil},
+		{"ext=1, other", "other, ext=1", nil},
+		{"max-age=60", "max-age=120", []FieldDiff{{Directive: HeaderMaxAge, Old: "max-age=60", New: "max-age=120"}}},
+		{"", "max-age=0", []FieldDiff{{Directive: HeaderMaxAge, New: "max-age=0"}}},
+		{"public", "", []FieldDiff{{Directive: HeaderPublic, Old: "public"}}},
+		{
+			`no-cache="Set-Cookie, X-Id"`, `no-cache="X-Id, Authorization"`,
+			[]FieldDiff{{
+				Directive: HeaderNoCache, Old: `no-cache="Set-Cookie, X-Id"`, New: `no-cache="Authorization, X-Id"`,
+				Added: []string{"Authorization"}, Removed: []string{"Set-Cookie"},
+			}},
+		},
+		{"private", `private="X-Id"`, []FieldDiff{{Directive: HeaderPrivate, Old: "private", New: `private="X-Id"`, Added: []string{"X-Id"}}}},
+		{"ext=1, ext=1", "ext=1, ext=2", []FieldDiff{{Directive: "extensions", Added: []string{"ext=2"}, Removed: []string{"ext=1"}}}},
+		{
+			"max-age=60, a", "s-maxage=60, b",
+			[]FieldDiff{
+				{Directive: HeaderMaxAge, Old: "max-age=60"},
+				{Directive: HeaderSMaxAge, New: "s-maxage=60"},
+				{Directive: "extensions", Added: []string{"b"}, Removed: []string{"a"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		a, err := NewResponseCacheDirective(tt.a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := NewResponseCacheDirective(tt.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Diff(a, b); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Diff(%q, %q) = %+v, want %+v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestFieldDiffString(t *testing.T) {
+	tests := []struct {
+		diff FieldDiff
+		want string
+	}{
+		{FieldDiff{Directive: HeaderMaxAge, Old: "max-age=60", New: "max-age=120"}, "max-age: max-age=60 -> max-age=120"},
+		{FieldDiff{Directive: HeaderPublic, Old: "public"}, "public: public -> (absent)"},
+		{FieldDiff{Directive: "extensions", Added: []string{"a", "b=1"}}, "extensions: +[a, b=1] -[]"},
+	}
+	for _, tt := range tests {
+		if got := tt.diff.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.diff, got, tt.want)
+		}
+	}
+}
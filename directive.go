@@ -6,7 +6,6 @@ import (
 	"math"
 	"net/http"
 	"net/textproto"
-	"strconv"
 	"strings"
 )
 
@@ -18,6 +17,18 @@ var (
 	ErrStaleIfErrorDeltaSeconds         = errors.New("invalid delta-seconds value in `stale-if-error` directive")
 	ErrStaleWhileRevalidateDeltaSeconds = errors.New("invalid delta-seconds value in `stale-while-revalidate` directive")
 
+	// ErrDeltaSecondsSyntax is the cause wrapped by the delta-seconds errors above
+	// when the value is not a non-negative decimal integer.
+	ErrDeltaSecondsSyntax = errors.New("not a non-negative decimal integer")
+
+	// ErrDeltaSecondsDate is the cause used instead of ErrDeltaSecondsSyntax when
+	// the value looks like an HTTP-date, a common mix-up with the Expires header.
+	ErrDeltaSecondsDate = errors.New("expects seconds, got what looks like a date; did you mean the Expires header?")
+
+	// ErrEmptyDeltaSeconds is the cause used when nothing follows the '=', as
+	// in `max-age=` or `s-maxage=,public`.
+	ErrEmptyDeltaSeconds = errors.New("empty value")
+
 	ErrPublicDirectiveValue          = errors.New("public directive does not accept a value")
 	ErrNoCacheDirectiveValue         = errors.New("no-cache directive does not accept a value")
 	ErrNoStoreDirectiveValue         = errors.New("no-store directive does not accept a value")
@@ -26,36 +37,104 @@ var (
 	ErrOnlyIfCachedDirectiveValue    = errors.New("only-if-cached directive does not accept a value")
 	ErrMustRevalidateDirectiveValue  = errors.New("must-revalidate directive does not accept a value")
 	ErrProxyRevalidateDirectiveValue = errors.New("proxy-revalidate directive does not accept a value")
+
+	// ErrRequestNoCacheFieldList is returned for `no-cache="field"` in a request.
+	// The field-list form is only defined for responses (RFC 9111 Section 5.2.2.4),
+	// on requests no-cache must be bare. It wraps ErrNoCacheDirectiveValue.
+	ErrRequestNoCacheFieldList = fmt.Errorf("%w: field lists are only allowed in response no-cache", ErrNoCacheDirectiveValue)
+
+	// ErrInvalidFieldName is returned in strict mode for a no-cache or private
+	// field list with a member that is not a valid field-name token (RFC 9110
+	// Section 5.1), e.g. containing a space, a ':' or a control character.
+	ErrInvalidFieldName = errors.New("invalid field name in field list")
 )
 
+// DirectiveError reports a problem with a single directive. It unwraps to both
+// the sentinel error and the underlying cause, so errors.Is keeps working for
+// either of them.
+type DirectiveError struct {
+	// Directive is the lower-cased name of the offending directive.
+	Directive string
+
+	// Err is the sentinel describing the problem, e.g. ErrMaxAgeDeltaSeconds.
+	Err error
+
+	// Cause is the underlying error, if any.
+	Cause error
+}
+
+func newDirectiveError(name string, err, cause error) error {
+	return &DirectiveError{Directive: name, Err: err, Cause: cause}
+}
+
+func (e *DirectiveError) Error() string {
+	if e.Cause == nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v: %v", e.Err, e.Cause)
+}
+
+func (e *DirectiveError) Unwrap() []error {
+	if e.Cause == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Err, e.Cause}
+}
+
+// DirectiveName returns the name of the directive err pertains to, if any.
+func DirectiveName(err error) (string, bool) {
+	var directiveErr *DirectiveError
+	if errors.As(err, &directiveErr) {
+		return directiveErr.Directive, true
+	}
+	return "", false
+}
+
+// errCacheExtension is returned by setToken and setPair when the directive name
+// is not one the directive type understands. The parser then records it as a
+// cache-extension via addExtension, keeping the original spelling of the value.
+var errCacheExtension = errors.New("cache-extension")
+
 type directive interface {
 	setToken(token string) error
 	setPair(key, val string) error
+	addExtension(ext string)
+	setComment(comment string)
 }
 
 func NewRequestCacheDirective(value string) (*RequestCacheDirective, error) {
-	directive := &RequestCacheDirective{MaxAge: -1, MaxStale: -1, MinFresh: -1}
-	if err := parseCacheControlv(directive, value); err != nil {
-		return nil, err
-	}
-	return directive, nil
+	return ParseRequest(value, nil)
+}
+
+// newRequestCacheDirective returns a request directive with every directive absent.
+func newRequestCacheDirective() *RequestCacheDirective {
+	return &RequestCacheDirective{MaxAge: -1, MaxStale: -1, MinFresh: -1, StaleIfError: -1, StaleWhileRevalidate: -1}
 }
 
 type RequestCacheDirective struct {
 	// max-age
 	// MaxAge is the maximum time in seconds that a response can be considered fresh.
 	// The client is not willing to accept a stale response if the max-stale directive
-	// is not present.
+	// is not present. It is -1 when the directive is absent.
 	MaxAge int32
 
 	// max-stale
 	// MaxStale is the maximum time in seconds that a client is willing to accept a
-	// stale response that has exceeded its freshness lifetime.
+	// stale response that has exceeded its freshness lifetime. It is -1 when the
+	// directive is absent; max-stale=0 means no staleness is tolerated at all.
 	MaxStale int32
 
+	// MaxStaleAny is true when max-stale was sent without a value: the client
+	// is willing to accept a stale response of any age (RFC 9111
+	// Section 5.2.1.2). MaxStale stays -1 in that case. When a header carries
+	// both forms the last one wins, like for any repeated directive.
+	MaxStaleAny bool
+
 	// min-fresh
 	// MinFresh is the minimum time in seconds that a response must remain fresh,
 	// calculated as the difference between its freshness lifetime and its current age.
+	// It is -1 when the directive is absent; min-fresh=0 only asks for a response
+	// that is fresh at all, which is no constraint beyond the default.
 	MinFresh int32
 
 	// no-cache
@@ -69,6 +148,25 @@ type RequestCacheDirective struct {
 	// any part of the request or response.
 	NoStore bool
 
+	// no-transform
+	// NoTransform is a boolean value that indicates whether intermediaries must
+	// not transform the request content.
+	NoTransform bool
+
+	// stale-if-error
+	// StaleIfError is the time in seconds past its freshness lifetime during
+	// which the client accepts a stale response when the origin fails
+	// (RFC 5861 Section 4). It is -1 when the directive is absent.
+	StaleIfError int32
+
+	// stale-while-revalidate
+	// StaleWhileRevalidate is the time in seconds past its freshness lifetime
+	// during which the client accepts a stale response while the cache
+	// revalidates it in the background. RFC 5861 only defines it for
+	// responses; it is honoured here like stale-if-error. It is -1 when the
+	// directive is absent.
+	StaleWhileRevalidate int32
+
 	// only-if-cached
 	// OnlyIfCached is a boolean value that indicates whether the client only
 	// wants to obtain a stored response and not send a request to the origin server.
@@ -76,28 +174,41 @@ type RequestCacheDirective struct {
 
 	// Extensions is a list of cache-extension tokens with optional values
 	// that can be used to extend the Cache-Control header field.
+	// Quoted values are kept in their original quoted form.
 	Extensions []string
+
+	// Comment is the text of a trailing parenthesized comment, such as
+	// "generated by X" in `max-age=60 (generated by X)`, stripped when parsing
+	// with ParseOptions.StripTrailingComment. It is not a directive: it is
+	// not serialized and does not take part in Equal.
+	Comment string
 }
 
 func (directive *RequestCacheDirective) setToken(token string) error {
 	switch token {
 	case HeaderMaxAge:
-		return ErrMaxAgeDeltaSeconds
-	case HeaderMaxStale:
-		return ErrMaxStaleDeltaSeconds
+		return newDirectiveError(HeaderMaxAge, ErrMaxAgeDeltaSeconds, nil)
 	case HeaderMinFresh:
-		return ErrMinFreshDeltaSeconds
+		return newDirectiveError(HeaderMinFresh, ErrMinFreshDeltaSeconds, nil)
+	case HeaderStaleIfError:
+		return newDirectiveError(HeaderStaleIfError, ErrStaleIfErrorDeltaSeconds, nil)
+	case HeaderStaleWhileRevalidate:
+		return newDirectiveError(HeaderStaleWhileRevalidate, ErrStaleWhileRevalidateDeltaSeconds, nil)
 	}
 
 	switch token {
+	case HeaderMaxStale:
+		directive.MaxStale, directive.MaxStaleAny = -1, true
 	case HeaderNoCache:
 		directive.NoCache = true
 	case HeaderNoStore:
 		directive.NoStore = true
+	case HeaderNoTransform:
+		directive.NoTransform = true
 	case HeaderOnlyIfCached:
 		directive.OnlyIfCached = true
 	default:
-		directive.Extensions = append(directive.Extensions, token)
+		return errCacheExtension
 	}
 	return nil
 }
@@ -105,44 +216,67 @@ func (directive *RequestCacheDirective) setToken(token string) error {
 func (directive *RequestCacheDirective) setPair(key, val string) error {
 	switch key {
 	case HeaderNoCache:
-		return ErrNoCacheDirectiveValue
+		return newDirectiveError(HeaderNoCache, ErrRequestNoCacheFieldList, nil)
 	case HeaderNoStore:
-		return ErrNoStoreDirectiveValue
+		return newDirectiveError(HeaderNoStore, ErrNoStoreDirectiveValue, nil)
+	case HeaderNoTransform:
+		return newDirectiveError(HeaderNoTransform, ErrNoTransformDirectiveValue, nil)
 	case HeaderOnlyIfCached:
-		return ErrOnlyIfCachedDirectiveValue
+		return newDirectiveError(HeaderOnlyIfCached, ErrOnlyIfCachedDirectiveValue, nil)
 	}
 
 	switch key {
 	case HeaderMaxAge:
 		deltaSec, err := validateDeltaSeconds(val)
 		if err != nil {
-			return fmt.Errorf("%w: %v", ErrMaxAgeDeltaSeconds, err)
+			return newDirectiveError(HeaderMaxAge, ErrMaxAgeDeltaSeconds, err)
 		}
 		directive.MaxAge = deltaSec
 	case HeaderMaxStale:
 		deltaSec, err := validateDeltaSeconds(val)
 		if err != nil {
-			return fmt.Errorf("%w: %v", ErrMaxStaleDeltaSeconds, err)
+			return newDirectiveError(HeaderMaxStale, ErrMaxStaleDeltaSeconds, err)
 		}
-		directive.MaxStale = deltaSec
+		directive.MaxStale, directive.MaxStaleAny = deltaSec, false
 	case HeaderMinFresh:
 		deltaSec, err := validateDeltaSeconds(val)
 		if err != nil {
-			return fmt.Errorf("%w: %v", ErrMinFreshDeltaSeconds, err)
+			return newDirectiveError(HeaderMinFresh, ErrMinFreshDeltaSeconds, err)
 		}
 		directive.MinFresh = deltaSec
+	case HeaderStaleIfError:
+		deltaSec, err := validateDeltaSeconds(val)
+		if err != nil {
+			return newDirectiveError(HeaderStaleIfError, ErrStaleIfErrorDeltaSeconds, err)
+		}
+		directive.StaleIfError = deltaSec
+	case HeaderStaleWhileRevalidate:
+		deltaSec, err := validateDeltaSeconds(val)
+		if err != nil {
+			return newDirectiveError(HeaderStaleWhileRevalidate, ErrStaleWhileRevalidateDeltaSeconds, err)
+		}
+		directive.StaleWhileRevalidate = deltaSec
 	default:
-		directive.Extensions = append(directive.Extensions, key+"="+val)
+		return errCacheExtension
 	}
 	return nil
 }
 
+func (directive *RequestCacheDirective) addExtension(ext string) {
+	directive.Extensions = append(directive.Extensions, ext)
+}
+
+func (directive *RequestCacheDirective) setComment(comment string) {
+	directive.Comment = comment
+}
+
 func NewResponseCacheDirective(value string) (*ResponseCacheDirective, error) {
-	directive := &ResponseCacheDirective{}
-	if err := parseCacheControlv(directive, value); err != nil {
-		return nil, err
-	}
-	return directive, nil
+	return ParseResponse(value, nil)
+}
+
+// newResponseCacheDirective returns a response directive with every directive absent.
+func newResponseCacheDirective() *ResponseCacheDirective {
+	return &ResponseCacheDirective{}
 }
 
 type ResponseCacheDirective struct {
@@ -153,12 +287,20 @@ type ResponseCacheDirective struct {
 	// NoCache is a map of field-name to boolean values that indicates whether
 	// a cache should not use a stored response to satisfy a request if any of the
 	// request-header field names are present in the list.
+	// Field names are canonicalized with http.CanonicalHeaderKey, so a name
+	// listed twice, in any case, is a single entry; NoCacheRaw keeps both.
+	// Strict parsing reports a Warning for such duplicates.
 	NoCache map[string]bool
 
 	// NoCachePresent is a boolean value that indicates whether the no-cache
 	// directive was present in the response.
 	NoCachePresent bool
 
+	// NoCacheRaw holds the no-cache field names exactly as the origin sent
+	// them, before canonicalization, for audit logging. Use NoCache for matching:
+	// when a bare no-cache was sent as well, NoCache is empty but NoCacheRaw is not.
+	NoCacheRaw []string
+
 	// NoStore is a boolean value that indicates whether a cache should not
 	// store any part of the response or request.
 	NoStore bool
@@ -174,12 +316,20 @@ type ResponseCacheDirective struct {
 	// Private is a map of field-name to boolean values that indicates whether
 	// the response is considered private, meaning it can only be cached by a cache
 	// that is specific to a particular user.
+	// Field names are canonicalized with http.CanonicalHeaderKey, so a name
+	// listed twice, in any case, is a single entry; PrivateRaw keeps both.
+	// Strict parsing reports a Warning for such duplicates.
 	Private map[string]bool
 
 	// PrivatePresent is a boolean value that indicates whether the private
 	// directive was present in the response.
 	PrivatePresent bool
 
+	// PrivateRaw holds the private field names exactly as the origin sent
+	// them, before canonicalization, for audit logging. Use Private for matching:
+	// when a bare private was sent as well, Private is empty but PrivateRaw is not.
+	PrivateRaw []string
+
 	// ProxyRevalidate is a boolean value that indicates whether a cache must
 	// revalidate a stored response on every request when the response was obtained
 	// from a proxy cache.
@@ -188,10 +338,19 @@ type ResponseCacheDirective struct {
 	// MaxAge is the maximum time in seconds that a response can be considered fresh.
 	MaxAge int32
 
+	// MaxAgePresent is a boolean value that indicates whether the max-age
+	// directive was present in the response. MaxAge is 0 when it was not,
+	// which tells it apart from max-age=0.
+	MaxAgePresent bool
+
 	// SMaxAge is the maximum time in seconds that a shared cache can consider
 	// a response to be fresh.
 	SMaxAge int32
 
+	// SMaxAgePresent is a boolean value that indicates whether the s-maxage
+	// directive was present in the response.
+	SMaxAgePresent bool
+
 	// Immutable is a boolean value that indicates whether the response payload
 	// is considered immutable and can be cached indefinitely.
 	Immutable bool
@@ -200,25 +359,66 @@ type ResponseCacheDirective struct {
 	// response when an error occurs.
 	StaleIfError int32
 
+	// StaleIfErrorPresent is a boolean value that indicates whether the
+	// stale-if-error directive was present in the response.
+	StaleIfErrorPresent bool
+
 	// StaleWhileRevalidate is the maximum time in seconds that a cache can serve
 	// a stale response while a background revalidation is being performed.
 	StaleWhileRevalidate int32
 
+	// StaleWhileRevalidatePresent is a boolean value that indicates whether the
+	// stale-while-revalidate directive was present in the response.
+	StaleWhileRevalidatePresent bool
+
 	// Extensions is a list of cache-extension tokens with optional values
 	// that can be used to extend the Cache-Control header field.
+	// Quoted values are kept in their original quoted form.
 	Extensions []string
+
+	// Comment is the text of a trailing parenthesized comment, such as
+	// "generated by X" in `max-age=60 (generated by X)`, stripped when parsing
+	// with ParseOptions.StripTrailingComment. It is not a directive: it is
+	// not serialized and does not take part in Equal.
+	Comment string
+}
+
+// maxAge, sMaxAge, staleIfError and staleWhileRevalidate return the value of
+// the delta-seconds directive, or -1 when it is absent, as in the request
+// directive fields.
+func (directive *ResponseCacheDirective) maxAge() int32 {
+	return optionalDeltaSeconds(directive.MaxAge, directive.MaxAgePresent)
+}
+
+func (directive *ResponseCacheDirective) sMaxAge() int32 {
+	return optionalDeltaSeconds(directive.SMaxAge, directive.SMaxAgePresent)
+}
+
+func (directive *ResponseCacheDirective) staleIfError() int32 {
+	return optionalDeltaSeconds(directive.StaleIfError, directive.StaleIfErrorPresent)
+}
+
+func (directive *ResponseCacheDirective) staleWhileRevalidate() int32 {
+	return optionalDeltaSeconds(directive.StaleWhileRevalidate, directive.StaleWhileRevalidatePresent)
+}
+
+func optionalDeltaSeconds(delta int32, present bool) int32 {
+	if !present {
+		return -1
+	}
+	return delta
 }
 
 func (directive *ResponseCacheDirective) setToken(token string) error {
 	switch token {
 	case HeaderMaxAge:
-		return ErrMaxAgeDeltaSeconds
+		return newDirectiveError(HeaderMaxAge, ErrMaxAgeDeltaSeconds, nil)
 	case HeaderSMaxAge:
-		return ErrSMaxAgeDeltaSeconds
+		return newDirectiveError(HeaderSMaxAge, ErrSMaxAgeDeltaSeconds, nil)
 	case HeaderStaleIfError:
-		return ErrStaleIfErrorDeltaSeconds
+		return newDirectiveError(HeaderStaleIfError, ErrStaleIfErrorDeltaSeconds, nil)
 	case HeaderStaleWhileRevalidate:
-		return ErrStaleWhileRevalidateDeltaSeconds
+		return newDirectiveError(HeaderStaleWhileRevalidate, ErrStaleWhileRevalidateDeltaSeconds, nil)
 	}
 
 	switch token {
@@ -231,15 +431,19 @@ func (directive *ResponseCacheDirective) setToken(token string) error {
 	case HeaderNoTransform:
 		directive.NoTransform = true
 	case HeaderPrivate:
+		// A bare private wins over any field list, see Validate
 		directive.PrivatePresent = true
+		directive.Private = nil
 	case HeaderMustRevalidate:
 		directive.MustRevalidate = true
 	case HeaderNoCache:
+		// A bare no-cache wins over any field list, see Validate
 		directive.NoCachePresent = true
+		directive.NoCache = nil
 	case HeaderProxyRevalidate:
 		directive.ProxyRevalidate = true
 	default:
-		directive.Extensions = append(directive.Extensions, token)
+		return errCacheExtension
 	}
 	return nil
 }
@@ -247,21 +451,23 @@ func (directive *ResponseCacheDirective) setToken(token string) error {
 func (directive *ResponseCacheDirective) setPair(key, val string) error {
 	switch key {
 	case HeaderMustRevalidate:
-		return ErrMustRevalidateDirectiveValue
+		return newDirectiveError(HeaderMustRevalidate, ErrMustRevalidateDirectiveValue, nil)
 	case HeaderNoStore:
-		return ErrNoStoreDirectiveValue
+		return newDirectiveError(HeaderNoStore, ErrNoStoreDirectiveValue, nil)
 	case HeaderNoTransform:
-		return ErrNoTransformDirectiveValue
+		return newDirectiveError(HeaderNoTransform, ErrNoTransformDirectiveValue, nil)
 	case HeaderPublic:
-		return ErrPublicDirectiveValue
+		return newDirectiveError(HeaderPublic, ErrPublicDirectiveValue, nil)
 	case HeaderProxyRevalidate:
-		return ErrProxyRevalidateDirectiveValue
+		return newDirectiveError(HeaderProxyRevalidate, ErrProxyRevalidateDirectiveValue, nil)
 	case HeaderImmutable:
-		return ErrImmutableDirectiveValue
+		return newDirectiveError(HeaderImmutable, ErrImmutableDirectiveValue, nil)
 	}
 
 	switch key {
 	case HeaderNoCache:
+		// After a bare no-cache the fields are only recorded in NoCacheRaw
+		bare := directive.NoCachePresent && len(directive.NoCache) == 0
 		directive.NoCachePresent = true
 
 		if directive.NoCache == nil {
@@ -270,10 +476,18 @@ func (directive *ResponseCacheDirective) setPair(key, val string) error {
 
 		vals := strings.Split(val, ",")
 		for _, v := range vals {
-			k := http.CanonicalHeaderKey(textproto.TrimString(v))
-			directive.NoCache[k] = true
+			raw := textproto.TrimString(v)
+			if raw == "" {
+				continue
+			}
+			directive.NoCacheRaw = append(directive.NoCacheRaw, raw)
+			if !bare {
+				directive.NoCache[http.CanonicalHeaderKey(raw)] = true
+			}
 		}
 	case HeaderPrivate:
+		// After a bare private the fields are only recorded in PrivateRaw
+		bare := directive.PrivatePresent && len(directive.Private) == 0
 		directive.PrivatePresent = true
 
 		if directive.Private == nil {
@@ -282,48 +496,76 @@ func (directive *ResponseCacheDirective) setPair(key, val string) error {
 
 		vals := strings.Split(val, ",")
 		for _, v := range vals {
-			k := http.CanonicalHeaderKey(textproto.TrimString(v))
-			directive.Private[k] = true
+			raw := textproto.TrimString(v)
+			if raw == "" {
+				continue
+			}
+			directive.PrivateRaw = append(directive.PrivateRaw, raw)
+			if !bare {
+				directive.Private[http.CanonicalHeaderKey(raw)] = true
+			}
 		}
 	case HeaderMaxAge:
 		deltaSec, err := validateDeltaSeconds(val)
 		if err != nil {
-			return fmt.Errorf("%w: %v", ErrMaxAgeDeltaSeconds, err)
+			return newDirectiveError(HeaderMaxAge, ErrMaxAgeDeltaSeconds, err)
 		}
-		directive.MaxAge = deltaSec
+		directive.MaxAge, directive.MaxAgePresent = deltaSec, true
 	case HeaderSMaxAge:
 		deltaSec, err := validateDeltaSeconds(val)
 		if err != nil {
-			return fmt.Errorf("%w: %v", ErrSMaxAgeDeltaSeconds, err)
+			return newDirectiveError(HeaderSMaxAge, ErrSMaxAgeDeltaSeconds, err)
 		}
-		directive.SMaxAge = deltaSec
+		directive.SMaxAge, directive.SMaxAgePresent = deltaSec, true
 	case HeaderStaleIfError:
 		deltaSec, err := validateDeltaSeconds(val)
 		if err != nil {
-			return fmt.Errorf("%w: %v", ErrStaleIfErrorDeltaSeconds, err)
+			return newDirectiveError(HeaderStaleIfError, ErrStaleIfErrorDeltaSeconds, err)
 		}
-		directive.StaleIfError = deltaSec
+		directive.StaleIfError, directive.StaleIfErrorPresent = deltaSec, true
 	case HeaderStaleWhileRevalidate:
 		deltaSec, err := validateDeltaSeconds(val)
 		if err != nil {
-			return fmt.Errorf("%w: %v", ErrStaleWhileRevalidateDeltaSeconds, err)
+			return newDirectiveError(HeaderStaleWhileRevalidate, ErrStaleWhileRevalidateDeltaSeconds, err)
 		}
-		directive.StaleWhileRevalidate = deltaSec
+		directive.StaleWhileRevalidate, directive.StaleWhileRevalidatePresent = deltaSec, true
 	default:
-		directive.Extensions = append(directive.Extensions, key+"="+val)
+		return errCacheExtension
 	}
 
 	return nil
 }
 
+func (directive *ResponseCacheDirective) addExtension(ext string) {
+	directive.Extensions = append(directive.Extensions, ext)
+}
+
+func (directive *ResponseCacheDirective) setComment(comment string) {
+	directive.Comment = comment
+}
+
+// validateDeltaSeconds parses delta-seconds (RFC 9111 Section 1.2.2). Values
+// larger than math.MaxInt32 are clamped to it, as the RFC asks caches to treat
+// overflowing values as the greatest integer they can represent.
 func validateDeltaSeconds(delta string) (int32, error) {
-	deltaSec, err := strconv.ParseUint(delta, 10, 32)
-	if err != nil {
-		switch err.(*strconv.NumError).Err {
-		case strconv.ErrRange:
-			return math.MaxInt32, nil
-		default:
-			return -1, err
+	if delta == "" {
+		return -1, ErrEmptyDeltaSeconds
+	}
+
+	var deltaSec int64
+	for i := 0; i < len(delta); i++ {
+		c := delta[i]
+		if c < '0' || c > '9' {
+			if looksLikeHTTPDate(delta) {
+				return -1, fmt.Errorf("%q: %w", delta, ErrDeltaSecondsDate)
+			}
+			if i > 0 {
+				return -1, fmt.Errorf("%q: unexpected %q after the digits: %w", delta, delta[i:], ErrDeltaSecondsSyntax)
+			}
+			return -1, fmt.Errorf("%q: %w", delta, ErrDeltaSecondsSyntax)
+		}
+		if deltaSec <= math.MaxInt32 {
+			deltaSec = deltaSec*10 + int64(c-'0')
 		}
 	}
 
@@ -332,3 +574,18 @@ func validateDeltaSeconds(delta string) (int32, error) {
 	}
 	return int32(deltaSec), nil
 }
+
+// looksLikeHTTPDate reports whether a delta-seconds value was probably meant as
+// an HTTP-date. An unquoted date is cut at its first ',' or space by the parser,
+// so a bare day name such as "Wed" counts too.
+func looksLikeHTTPDate(value string) bool {
+	if _, err := http.ParseTime(value); err == nil {
+		return true
+	}
+	switch strings.ToLower(value) {
+	case "mon", "tue", "wed", "thu", "fri", "sat", "sun",
+		"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday":
+		return true
+	}
+	return false
+}
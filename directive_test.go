@@ -0,0 +1,193 @@
+package cache
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestResponseDirectiveNameCase(t *testing.T) {
+	tests := []struct {
+		value        string
+		upper, mixed string
+	}{
+		{"public", "PUBLIC", "Public"},
+		{"private", "PRIVATE", "PriVate"},
+		{`private="set-cookie"`, `PRIVATE="SET-COOKIE"`, `Private="set-cookie"`},
+		{"no-cache", "NO-CACHE", "No-Cache"},
+		{`no-cache="set-cookie, x-request-id"`, `NO-CACHE="SET-COOKIE, X-REQUEST-ID"`, `No-Cache="set-cookie, x-request-id"`},
+		{"no-cache=set-cookie", "NO-CACHE=SET-COOKIE", "nO-cAcHe=set-cookie"},
+		{"no-store", "NO-STORE", "No-Store"},
+		{"max-age=60", "MAX-AGE=60", "Max-Age=60"},
+		{"s-maxage=60", "S-MAXAGE=60", "S-MaxAge=60"},
+		{"must-revalidate", "MUST-REVALIDATE", "Must-Revalidate"},
+		{"proxy-revalidate", "PROXY-REVALIDATE", "Proxy-Revalidate"},
+		{"no-transform", "NO-TRANSFORM", "No-Transform"},
+		{"immutable", "IMMUTABLE", "Immutable"},
+		{"stale-while-revalidate=30", "STALE-WHILE-REVALIDATE=30", "Stale-While-Revalidate=30"},
+		{"stale-if-error=30", "STALE-IF-ERROR=30", "Stale-If-Error=30"},
+		{"ext=value", "EXT=value", "Ext=value"},
+	}
+	for _, tt := range tests {
+		want, err := NewResponseCacheDirective(tt.value)
+		if err != nil {
+			t.Fatalf("NewResponseCacheDirective(%q): %v", tt.value, err)
+		}
+		for _, value := range []string{tt.upper, tt.mixed} {
+			got, err := NewResponseCacheDirective(value)
+			if err != nil {
+				t.Errorf("NewResponseCacheDirective(%q): %v", value, err)
+				continue
+			}
+			if !got.Equal(want) {
+				t.Errorf("%q parses as %q, want %q", value, got.String(), want.String())
+			}
+		}
+	}
+}
+
+func TestRequestDirectiveNameCase(t *testing.T) {
+	tests := []struct {
+		value        string
+		upper, mixed string
+	}{
+		{"no-cache", "NO-CACHE", "No-Cache"},
+		{"no-store", "NO-STORE", "No-Store"},
+		{"no-transform", "NO-TRANSFORM", "No-Transform"},
+		{"only-if-cached", "ONLY-IF-CACHED", "Only-If-Cached"},
+		{"max-age=60", "MAX-AGE=60", "Max-Age=60"},
+		{"max-stale", "MAX-STALE", "Max-Stale"},
+		{"max-stale=60", "MAX-STALE=60", "Max-Stale=60"},
+		{"min-fresh=60", "MIN-FRESH=60", "Min-Fresh=60"},
+		{"stale-if-error=60", "STALE-IF-ERROR=60", "Stale-If-Error=60"},
+		{"stale-while-revalidate=60", "STALE-WHILE-REVALIDATE=60", "Stale-While-Revalidate=60"},
+		{"ext=value", "EXT=value", "Ext=value"},
+	}
+	for _, tt := range tests {
+		want, err := NewRequestCacheDirective(tt.value)
+		if err != nil {
+			t.Fatalf("NewRequestCacheDirective(%q): %v", tt.value, err)
+		}
+		for _, value := range []string{tt.upper, tt.mixed} {
+			got, err := NewRequestCacheDirective(value)
+			if err != nil {
+				t.Errorf("NewRequestCacheDirective(%q): %v", value, err)
+				continue
+			}
+			if !got.Equal(want) {
+				t.Errorf("%q parses as %q, want %q", value, got.String(), want.String())
+			}
+		}
+	}
+}
+
+func TestUpperCaseFieldListIsCanonicalized(t *testing.T) {
+	directive, err := NewResponseCacheDirective(`NO-CACHE="SET-COOKIE, x-request-id", PRIVATE=AUTHORIZATION`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Set-Cookie", "X-Request-Id"} {
+		if !directive.NoCache[name] {
+			t.Errorf("NoCache = %v, want %s", directive.NoCache, name)
+		}
+	}
+	if !directive.Private["Authorization"] {
+		t.Errorf("Private = %v, want Authorization", directive.Private)
+	}
+	if got, want := directive.String(), `private="Authorization", no-cache="Set-Cookie, X-Request-Id"`; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func BenchmarkValidateDeltaSeconds(b *testing.B) {
+	for _, delta := range []string{"0", "86400", "99999999999"} {
+		b.Run(delta, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := validateDeltaSeconds(delta); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateDeltaSeconds(t *testing.T) {
+	tests := []struct {
+		delta string
+		want  int32
+		err   error
+	}{
+		{"0", 0, nil},
+		{"60", 60, nil},
+		{"2147483647", math.MaxInt32, nil},
+		{"2147483648", math.MaxInt32, nil},
+		{"99999999999999999999", math.MaxInt32, nil},
+		{"", -1, ErrEmptyDeltaSeconds},
+		{"Wed", -1, ErrDeltaSecondsDate},
+		{"-1", -1, ErrDeltaSecondsSyntax},
+		{"6a", -1, ErrDeltaSecondsSyntax},
+	}
+	for _, tt := range tests {
+		got, err := validateDeltaSeconds(tt.delta)
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("validateDeltaSeconds(%q) = %d, %v, want %d, %v", tt.delta, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestDeltaSecondsOverflowAndErrors(t *testing.T) {
+	directive, err := NewResponseCacheDirective("max-age=18446744073709551616, s-maxage=4294967296")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if directive.MaxAge != math.MaxInt32 || directive.SMaxAge != math.MaxInt32 {
+		t.Errorf("MaxAge, SMaxAge = %d, %d, want both clamped to %d", directive.MaxAge, directive.SMaxAge, math.MaxInt32)
+	}
+
+	for _, value := range []string{"max-age=abc", "max-age=-1", "max-age=1.5", "max-age=+1"} {
+		_, err := NewResponseCacheDirective(value)
+		if !errors.Is(err, ErrMaxAgeDeltaSeconds) {
+			t.Errorf("NewResponseCacheDirective(%q) = %v, want ErrMaxAgeDeltaSeconds", value, err)
+		}
+	}
+}
+
+func TestEmptyDeltaSeconds(t *testing.T) {
+	response := []struct {
+		name string
+		err  error
+	}{
+		{HeaderMaxAge, ErrMaxAgeDeltaSeconds},
+		{HeaderSMaxAge, ErrSMaxAgeDeltaSeconds},
+		{HeaderStaleIfError, ErrStaleIfErrorDeltaSeconds},
+		{HeaderStaleWhileRevalidate, ErrStaleWhileRevalidateDeltaSeconds},
+	}
+	for _, tt := range response {
+		for _, value := range []string{tt.name + "=", "public, " + tt.name + "=, no-store", tt.name + "=,public"} {
+			_, err := NewResponseCacheDirective(value)
+			if !errors.Is(err, tt.err) || !errors.Is(err, ErrEmptyDeltaSeconds) {
+				t.Errorf("NewResponseCacheDirective(%q) = %v, want %v and ErrEmptyDeltaSeconds", value, err, tt.err)
+			}
+		}
+	}
+
+	request := []struct {
+		name string
+		err  error
+	}{
+		{HeaderMaxAge, ErrMaxAgeDeltaSeconds},
+		{HeaderMaxStale, ErrMaxStaleDeltaSeconds},
+		{HeaderMinFresh, ErrMinFreshDeltaSeconds},
+		{HeaderStaleIfError, ErrStaleIfErrorDeltaSeconds},
+		{HeaderStaleWhileRevalidate, ErrStaleWhileRevalidateDeltaSeconds},
+	}
+	for _, tt := range request {
+		for _, value := range []string{tt.name + "=", "no-cache, " + tt.name + "=, no-store", tt.name + "=,no-cache"} {
+			_, err := NewRequestCacheDirective(value)
+			if !errors.Is(err, tt.err) || !errors.Is(err, ErrEmptyDeltaSeconds) {
+				t.Errorf("NewRequestCacheDirective(%q) = %v, want %v and ErrEmptyDeltaSeconds", value, err, tt.err)
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"math"
+	"time"
+)
+
+// deltaDuration converts a delta-seconds field to a time.Duration. The bool is
+// false when the directive is absent. math.MaxInt32 seconds fits comfortably
+// in a time.Duration, so the conversion cannot overflow.
+func deltaDuration(delta int32) (time.Duration, bool) {
+	if delta < 0 {
+		return 0, false
+	}
+	return time.Duration(delta) * time.Second, true
+}
+
+// MaxAgeDuration returns max-age as a time.Duration and whether it was set.
+func (directive *RequestCacheDirective) MaxAgeDuration() (time.Duration, bool) {
+	return deltaDuration(directive.MaxAge)
+}
+
+// MaxStaleDuration returns max-stale as a time.Duration and whether it was set.
+func (directive *RequestCacheDirective) MaxStaleDuration() (time.Duration, bool) {
+	return deltaDuration(directive.MaxStale)
+}
+
+// MinFreshDuration returns min-fresh as a time.Duration and whether it was set.
+func (directive *RequestCacheDirective) MinFreshDuration() (time.Duration, bool) {
+	return deltaDuration(directive.MinFresh)
+}
+
+// StaleIfErrorDuration returns stale-if-error as a time.Duration and whether
+// it was set.
+func (directive *RequestCacheDirective) StaleIfErrorDuration() (time.Duration, bool) {
+	return deltaDuration(directive.StaleIfError)
+}
+
+// StaleWhileRevalidateDuration returns stale-while-revalidate as a
+// time.Duration and whether it was set.
+func (directive *RequestCacheDirective) StaleWhileRevalidateDuration() (time.Duration, bool) {
+	return deltaDuration(directive.StaleWhileRevalidate)
+}
+
+// MaxAgeDuration returns max-age as a time.Duration and whether it was set.
+func (directive *ResponseCacheDirective) MaxAgeDuration() (time.Duration, bool) {
+	return deltaDuration(directive.maxAge())
+}
+
+// SMaxAgeDuration returns s-maxage as a time.Duration and whether it was set.
+func (directive *ResponseCacheDirective) SMaxAgeDuration() (time.Duration, bool) {
+	return deltaDuration(directive.sMaxAge())
+}
+
+// StaleIfErrorDuration returns stale-if-error as a time.Duration and whether
+// it was set.
+func (directive *ResponseCacheDirective) StaleIfErrorDuration() (time.Duration, bool) {
+	return deltaDuration(directive.staleIfError())
+}
+
+// StaleWhileRevalidateDuration returns stale-while-revalidate as a
+// time.Duration and whether it was set.
+func (directive *ResponseCacheDirective) StaleWhileRevalidateDuration() (time.Duration, bool) {
+	return deltaDuration(directive.staleWhileRevalidate())
+}
+
+// durationDelta converts d to delta-seconds. Sub-second precision is truncated
+// (1.9s becomes 1), negative durations become 0 and durations beyond
+// math.MaxInt32 seconds are clamped, matching how parsed values are clamped.
+func durationDelta(d time.Duration) int32 {
+	if d <= 0 {
+		return 0
+	}
+	seconds := d / time.Second
+	if seconds > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return int32(seconds)
+}
+
+// WithMaxAgeDuration sets max-age from d, rounded down to whole seconds, and
+// returns the directive so calls can be chained:
+//
+//	req, _ := NewRequestCacheDirective("")
+//	req.WithMaxAgeDuration(90 * time.Second).String() // "max-age=90"
+func (directive *RequestCacheDirective) WithMaxAgeDuration(d time.Duration) *RequestCacheDirective {
+	directive.MaxAge = durationDelta(d)
+	return directive
+}
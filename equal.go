@@ -0,0 +1,64 @@
+package cache
+
+import "hash/fnv"
+
+// Equal reports whether both request directives carry the same directives.
+// Extensions are compared regardless of their order.
+func (directive *RequestCacheDirective) Equal(other *RequestCacheDirective) bool {
+	return directive.StringCanonical() == other.StringCanonical()
+}
+
+// Equal reports whether both response directives carry the same directives.
+// Field lists and extensions are compared regardless of their order.
+func (directive *ResponseCacheDirective) Equal(other *ResponseCacheDirective) bool {
+	return directive.StringCanonical() == other.StringCanonical()
+}
+
+// Matches parses headerValue as a response Cache-Control value and reports
+// whether it carries the same directives as the receiver, see Equal. It is
+// meant for assertions in tests. The parse error is returned when headerValue
+// is malformed.
+func (directive *ResponseCacheDirective) Matches(headerValue string) (bool, error) {
+	other, err := NewResponseCacheDirective(headerValue)
+	if err != nil {
+		return false, err
+	}
+	return directive.Equal(other), nil
+}
+
+// Hash returns a 64-bit FNV-1a hash of the canonical serialization (see
+// StringCanonical), so it ignores the order of field lists and extensions and
+// two Equal directives always hash identically.
+//
+// The hash is stable across processes, platforms and releases as long as
+// StringCanonical does not change; any change to the canonical form will be
+// treated as a breaking change.
+func (directive *ResponseCacheDirective) Hash() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(directive.StringCanonical()))
+	return h.Sum64()
+}
+
+// IsZero reports whether no directive is set, i.e. the directive would
+// serialize to an empty header and need not be sent at all. Absent
+// delta-seconds are -1 in a request, so the Go zero value is not zero: it
+// reads as max-age=0, min-fresh=0 and so on. Start from
+// NewRequestCacheDirective("") or ParseRequest instead.
+func (directive *RequestCacheDirective) IsZero() bool {
+	return directive.MaxAge < 0 && directive.MaxStale < 0 && !directive.MaxStaleAny && directive.MinFresh < 0 &&
+		directive.StaleIfError < 0 && directive.StaleWhileRevalidate < 0 &&
+		!directive.NoCache && !directive.NoStore && !directive.NoTransform && !directive.OnlyIfCached &&
+		len(directive.Extensions) == 0
+}
+
+// IsZero reports whether no directive is set, i.e. the directive would
+// serialize to an empty header and need not be sent at all. The presence flags
+// count: a bare `private` or `no-cache` is a directive.
+func (directive *ResponseCacheDirective) IsZero() bool {
+	return !directive.MaxAgePresent && !directive.SMaxAgePresent &&
+		!directive.StaleIfErrorPresent && !directive.StaleWhileRevalidatePresent &&
+		!directive.NoCachePresent && !directive.PrivatePresent &&
+		!directive.MustRevalidate && !directive.NoStore && !directive.NoTransform &&
+		!directive.Public && !directive.ProxyRevalidate && !directive.Immutable &&
+		len(directive.Extensions) == 0
+}
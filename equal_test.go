@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMatches(t *testing.T) {
+	directive, err := NewResponseCacheDirective(`public, max-age=60, no-cache="X-Id, Set-Cookie", ext=1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{`public, max-age=60, no-cache="X-Id, Set-Cookie", ext=1`, true},
+		{`ext=1, NO-CACHE="set-cookie, x-id", Max-Age=060, public`, true},
+		{`public, max-age=60, no-cache="Set-Cookie", ext=1`, false},
+		{`public, max-age=60, no-cache="X-Id, Set-Cookie"`, false},
+		{`public, max-age=61, no-cache="X-Id, Set-Cookie", ext=1`, false},
+	}
+	for _, tt := range tests {
+		got, err := directive.Matches(tt.header)
+		if err != nil || got != tt.want {
+			t.Errorf("Matches(%q) = %v, %v, want %v, nil", tt.header, got, err, tt.want)
+		}
+	}
+
+	if got, err := directive.Matches("max-age=abc"); got || !errors.Is(err, ErrMaxAgeDeltaSeconds) {
+		t.Errorf("Matches(%q) = %v, %v, want false, ErrMaxAgeDeltaSeconds", "max-age=abc", got, err)
+	}
+}
+
+func TestResponseIsZero(t *testing.T) {
+	if directive := (ResponseCacheDirective{}); !directive.IsZero() {
+		t.Errorf("ResponseCacheDirective{}.IsZero() = false, want true")
+	}
+
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"", true},
+		{" , ", true},
+		{"max-age=0", false},
+		{"s-maxage=0", false},
+		{"stale-if-error=0", false},
+		{"private", false},
+		{`no-cache=""`, false},
+		{"ext", false},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.header)
+		if err != nil {
+			t.Errorf("NewResponseCacheDirective(%q): %v", tt.header, err)
+			continue
+		}
+		if got := directive.IsZero(); got != tt.want {
+			t.Errorf("%q: IsZero() = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestRequestIsZero(t *testing.T) {
+	if directive := (RequestCacheDirective{}); directive.IsZero() {
+		t.Errorf("RequestCacheDirective{}.IsZero() = true, want false: it reads as max-age=0")
+	}
+
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"", true},
+		{"max-age=0", false},
+		{"max-stale", false},
+		{"only-if-cached", false},
+	}
+	for _, tt := range tests {
+		directive, err := NewRequestCacheDirective(tt.header)
+		if err != nil {
+			t.Errorf("NewRequestCacheDirective(%q): %v", tt.header, err)
+			continue
+		}
+		if got := directive.IsZero(); got != tt.want {
+			t.Errorf("%q: IsZero() = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		equal bool
+	}{
+		{"public, max-age=60", "max-age=60, public", true},
+		{`no-cache="X-Id, Set-Cookie", ext-b, ext-a=1`, `ext-a=1, NO-CACHE="set-cookie,x-id", ext-b`, true},
+		{"max-age=060", "max-age=60", true},
+		{"max-age=60", "max-age=61", false},
+		{"", "max-age=0", false},
+		{"private", `private="X-Id"`, false},
+		{"ext=1", "ext=2", false},
+	}
+	for _, tt := range tests {
+		a, err := NewResponseCacheDirective(tt.a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := NewResponseCacheDirective(tt.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := a.Hash() == b.Hash(); got != tt.equal {
+			t.Errorf("Hash(%q) == Hash(%q) = %v, want %v", tt.a, tt.b, got, tt.equal)
+		}
+	}
+}
+
+// TestHashStable pins hash values: they must not change across releases.
+func TestHashStable(t *testing.T) {
+	tests := []struct {
+		value string
+		want  uint64
+	}{
+		{"", 0xcbf29ce484222325},
+		{"max-age=60, public", 0xf8248575f5e0e27d},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := directive.Hash(); got != tt.want {
+			t.Errorf("Hash(%q) = %#x, want %#x", tt.value, got, tt.want)
+		}
+	}
+}
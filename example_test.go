@@ -0,0 +1,22 @@
+package cache_test
+
+import (
+	"fmt"
+
+	cache "github.com/davidleitw/cache-control"
+)
+
+func ExampleRequestCacheDirective_ForcesRevalidation() {
+	// A browser reload sends a bare no-cache: the cache must revalidate
+	// whatever it has stored before using it.
+	req, _ := cache.NewRequestCacheDirective("no-cache")
+	fmt.Println(req.ForcesRevalidation())
+
+	// The response no-cache may name fields instead, and only those must be
+	// revalidated.
+	resp, _ := cache.NewResponseCacheDirective(`no-cache="Set-Cookie"`)
+	fmt.Println(resp.MustRevalidateField("Set-Cookie"), resp.MustRevalidateField("Content-Type"))
+	// Output:
+	// true
+	// true false
+}
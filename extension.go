@@ -0,0 +1,127 @@
+package cache
+
+import "strings"
+
+// HasExtensions reports whether the header carried any directive that is not
+// defined for requests by RFC 9111.
+func (directive *RequestCacheDirective) HasExtensions() bool {
+	return len(directive.Extensions) > 0
+}
+
+// HasExtensions reports whether the header carried any directive that is not
+// defined for responses by RFC 9111 or RFC 5861/8246.
+func (directive *ResponseCacheDirective) HasExtensions() bool {
+	return len(directive.Extensions) > 0
+}
+
+// RemoveExtension removes every extension called name, compared
+// case-insensitively, and reports whether any was removed. The remaining
+// extensions keep their relative order, and since String writes directives in
+// a fixed order, removing an extension never reshuffles the rest of the header.
+func (directive *RequestCacheDirective) RemoveExtension(name string) bool {
+	var removed bool
+	directive.Extensions, removed = removeExtension(directive.Extensions, name)
+	return removed
+}
+
+// RemoveExtension removes every extension called name, compared
+// case-insensitively, and reports whether any was removed. The remaining
+// extensions keep their relative order, and since String writes directives in
+// a fixed order, removing an extension never reshuffles the rest of the header.
+func (directive *ResponseCacheDirective) RemoveExtension(name string) bool {
+	var removed bool
+	directive.Extensions, removed = removeExtension(directive.Extensions, name)
+	return removed
+}
+
+func removeExtension(exts []string, name string) ([]string, bool) {
+	kept := exts[:0]
+	for _, ext := range exts {
+		if !strings.EqualFold(extensionName(ext), name) {
+			kept = append(kept, ext)
+		}
+	}
+	removed := len(kept) != len(exts)
+	if len(kept) == 0 {
+		kept = nil
+	}
+	return kept, removed
+}
+
+// extensionName returns the directive name of an Extensions entry.
+func extensionName(ext string) string {
+	if i := strings.IndexByte(ext, '='); i >= 0 {
+		return ext[:i]
+	}
+	return ext
+}
+
+// ExtensionValue returns the value of the first extension called name,
+// compared case-insensitively, and whether it was present. The entry is split
+// at its first '=' and a quoted value is unquoted, so `ext="a=b"` yields a=b.
+// A bare extension has an empty value.
+func (directive *RequestCacheDirective) ExtensionValue(name string) (string, bool) {
+	return extensionValue(directive.Extensions, name)
+}
+
+// ExtensionValue returns the value of the first extension called name,
+// compared case-insensitively, and whether it was present. The entry is split
+// at its first '=' and a quoted value is unquoted, so `ext="a=b"` yields a=b.
+// A bare extension has an empty value.
+func (directive *ResponseCacheDirective) ExtensionValue(name string) (string, bool) {
+	return extensionValue(directive.Extensions, name)
+}
+
+func extensionValue(exts []string, name string) (string, bool) {
+	for _, ext := range exts {
+		key := extensionName(ext)
+		if !strings.EqualFold(key, name) {
+			continue
+		}
+
+		val := strings.TrimPrefix(ext[len(key):], "=")
+		if val != "" && val[0] == '"' {
+			if eaten, unquoted, _ := parseQuotedString(val, SubstituteInvalidQuotedByte); eaten == len(val) {
+				return unquoted, true
+			}
+		}
+		return val, true
+	}
+	return "", false
+}
+
+// Get looks up any directive by name, compared case-insensitively, and returns
+// its value and whether it is present. Standard directives are looked up in
+// their fields and anything else in the extensions, see ExtensionValue, so
+// generic tooling does not have to switch on field names.
+//
+// Values are unquoted: delta-seconds come back as decimal numbers, field lists
+// as canonicalized names sorted and joined with ", ", and boolean directives as
+// well as bare no-cache or private have an empty value.
+func (directive *ResponseCacheDirective) Get(name string) (value string, present bool) {
+	lower := strings.ToLower(name)
+	if !isResponseDirective(lower) {
+		return extensionValue(directive.Extensions, name)
+	}
+
+	fragment := directive.fragment(lower)
+	if fragment == "" {
+		return "", false
+	}
+	_, value, _ = strings.Cut(fragment, "=")
+	if value != "" && value[0] == '"' {
+		_, value, _ = parseQuotedString(value, SubstituteInvalidQuotedByte)
+	}
+	return value, true
+}
+
+// isResponseDirective reports whether name is a lower-cased directive with a
+// field in ResponseCacheDirective.
+func isResponseDirective(name string) bool {
+	for _, known := range responseDirectives {
+		if name == known {
+			return true
+		}
+	}
+	return false
+}
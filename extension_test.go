@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRemoveExtensionKeepsOrder(t *testing.T) {
+	const value = `public, max-age=60, x-track=1, ext-a, x-b="c d", x-track, ext-z=2`
+	directive, err := NewResponseCacheDirective(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !directive.RemoveExtension("X-Track") {
+		t.Fatal("RemoveExtension(X-Track) = false")
+	}
+	if directive.RemoveExtension("x-track") {
+		t.Error("RemoveExtension removed x-track twice")
+	}
+
+	got := directive.String()
+	if want := `public, max-age=60, ext-a, x-b="c d", ext-z=2`; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if !isSubsequence(strings.Split(got, ", "), strings.Split(value, ", ")) {
+		t.Errorf("%q is not a subsequence of %q", got, value)
+	}
+}
+
+func isSubsequence(sub, s []string) bool {
+	i := 0
+	for _, e := range s {
+		if i < len(sub) && sub[i] == e {
+			i++
+		}
+	}
+	return i == len(sub)
+}
+
+func TestBase64ExtensionValues(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   string
+		string string
+	}{
+		{"sig=abc123==", "abc123==", `sig="abc123=="`},
+		{`sig="abc123=="`, "abc123==", `sig="abc123=="`},
+		{"sig=YQ=", "YQ=", `sig="YQ="`},
+		{"max-age=60, sig=abc+/9==, public", "abc+/9==", `public, max-age=60, sig="abc+/9=="`},
+		{"sig=abc123", "abc123", "sig=abc123"},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, ok := directive.ExtensionValue("sig"); !ok || got != tt.want {
+			t.Errorf("%q: ExtensionValue(sig) = %q, %v, want %q", tt.value, got, ok, tt.want)
+		}
+		header := directive.String()
+		if header != tt.string {
+			t.Errorf("%q: String() = %q, want %q", tt.value, header, tt.string)
+		}
+		again, err := NewResponseCacheDirective(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := again.ExtensionValue("sig"); got != tt.want {
+			t.Errorf("%q round-trips to ExtensionValue(sig) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package cache
+
+import "net/http"
+
+// MustRevalidateField reports whether the header field name may not be served
+// from a stored response without revalidating it first, per the response
+// no-cache directive (RFC 9111 Section 5.2.2.4):
+//
+//   - without no-cache it returns false;
+//   - for a bare no-cache it returns true for every field, as the whole
+//     response must be revalidated;
+//   - for no-cache="field-list" it returns true only for the listed fields,
+//     the rest of the response may be reused without revalidation.
+//
+// name is canonicalized with http.CanonicalHeaderKey before the lookup.
+func (directive *ResponseCacheDirective) MustRevalidateField(name string) bool {
+	return directive.NoCacheContains(name)
+}
+
+// ForceRevalidateAlways reports whether every reuse of the stored response
+// must be revalidated with the origin first, the "always revalidate" posture:
+//
+//   - a bare no-cache (RFC 9111 Section 5.2.2.4);
+//   - max-age=0 with must-revalidate, which makes the response stale on
+//     arrival while forbidding to serve it stale (Section 5.2.2.2).
+//
+// A no-cache="field-list" does not count: it only forces revalidation before
+// reusing the listed fields, see MustRevalidateField, and the rest of the
+// response follows its normal freshness.
+func (directive *ResponseCacheDirective) ForceRevalidateAlways() bool {
+	if directive.NoCachePresent && len(directive.NoCache) == 0 {
+		return true
+	}
+	return directive.MaxAgePresent && directive.MaxAge == 0 && directive.MustRevalidate
+}
+
+// NoCacheContains reports whether no-cache applies to the header field name:
+// it is false without no-cache, true for every name with a bare no-cache and
+// true for the listed names with no-cache="field-list". name is canonicalized
+// with http.CanonicalHeaderKey, so "set-cookie" matches Set-Cookie. Nothing is
+// allocated for names already in canonical form.
+func (directive *ResponseCacheDirective) NoCacheContains(name string) bool {
+	return fieldListContains(directive.NoCachePresent, directive.NoCache, name)
+}
+
+// PrivateContains is like NoCacheContains for the private directive: a bare
+// private covers every field.
+func (directive *ResponseCacheDirective) PrivateContains(name string) bool {
+	return fieldListContains(directive.PrivatePresent, directive.Private, name)
+}
+
+func fieldListContains(present bool, fields map[string]bool, name string) bool {
+	if !present {
+		return false
+	}
+	if len(fields) == 0 {
+		return true
+	}
+	return fields[http.CanonicalHeaderKey(name)]
+}
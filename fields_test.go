@@ -0,0 +1,161 @@
+package cache
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMustRevalidateField(t *testing.T) {
+	tests := []struct {
+		value string
+		field string
+		want  bool
+	}{
+		{"max-age=60", "Set-Cookie", false},
+		{"no-cache", "Set-Cookie", true},
+		{"no-cache", "Content-Type", true},
+		{`no-cache="Set-Cookie"`, "Set-Cookie", true},
+		{`no-cache="Set-Cookie"`, "set-cookie", true},
+		{`no-cache="Set-Cookie"`, "Content-Type", false},
+		{`no-cache="Set-Cookie, X-Id"`, "x-id", true},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := directive.MustRevalidateField(tt.field); got != tt.want {
+			t.Errorf("%q: MustRevalidateField(%q) = %v, want %v", tt.value, tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestStrictFieldNames(t *testing.T) {
+	tests := []struct {
+		value string
+		err   error
+	}{
+		{`no-cache="Set-Cookie, X-Id"`, nil},
+		{`no-cache="Set-Cookie,,X-Id"`, nil},
+		{`private=", Set-Cookie,"`, nil},
+		{`no-cache=""`, nil},
+		{`no-cache="Set Cookie"`, ErrInvalidFieldName},
+		{`no-cache="Set-Cookie:"`, ErrInvalidFieldName},
+		{`private="X-Id, Content:Type"`, ErrInvalidFieldName},
+		{`private="X-Id, a@b"`, ErrInvalidFieldName},
+		{`no-cache="Set-Cookie` + "\x01" + `"`, ErrSyntax},
+	}
+	for _, tt := range tests {
+		_, err := ParseResponse(tt.value, &ParseOptions{Strict: true})
+		if !errors.Is(err, tt.err) {
+			t.Errorf("strict: ParseResponse(%q) = %v, want %v", tt.value, err, tt.err)
+		}
+
+		if _, err := NewResponseCacheDirective(tt.value); err != nil {
+			t.Errorf("lenient: NewResponseCacheDirective(%q): %v", tt.value, err)
+		}
+	}
+}
+
+func TestFieldListContains(t *testing.T) {
+	tests := []struct {
+		value            string
+		field            string
+		noCache, private bool
+	}{
+		{"max-age=60", "Set-Cookie", false, false},
+		{"no-cache, private", "Set-Cookie", true, true},
+		{"no-cache, private", "anything", true, true},
+		{`no-cache="Set-Cookie", private="X-Id"`, "set-cookie", true, false},
+		{`no-cache="Set-Cookie", private="X-Id"`, "SET-COOKIE", true, false},
+		{`no-cache="Set-Cookie", private="X-Id"`, "x-id", false, true},
+		{`no-cache="set-cookie", private="x-ID"`, "Set-Cookie", true, false},
+		{`no-cache="set-cookie", private="x-ID"`, "X-Id", false, true},
+		{`no-cache="Set-Cookie", private="X-Id"`, "Content-Type", false, false},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := directive.NoCacheContains(tt.field); got != tt.noCache {
+			t.Errorf("%q: NoCacheContains(%q) = %v, want %v", tt.value, tt.field, got, tt.noCache)
+		}
+		if got := directive.PrivateContains(tt.field); got != tt.private {
+			t.Errorf("%q: PrivateContains(%q) = %v, want %v", tt.value, tt.field, got, tt.private)
+		}
+	}
+
+	directive, err := NewResponseCacheDirective(`no-cache="Set-Cookie"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { directive.NoCacheContains("Set-Cookie") }); allocs != 0 {
+		t.Errorf("NoCacheContains allocates %v times, want 0", allocs)
+	}
+}
+
+func TestDuplicateFieldNames(t *testing.T) {
+	tests := []struct {
+		value    string
+		warnings []string
+		fields   []string
+	}{
+		{`no-cache="Set-Cookie, Set-Cookie"`, []string{"no-cache: duplicate field name Set-Cookie"}, []string{"Set-Cookie"}},
+		{`no-cache="Set-Cookie, set-cookie, X-Id"`, []string{"no-cache: duplicate field name Set-Cookie"}, []string{"Set-Cookie", "X-Id"}},
+		{`private="X-Id, Set-Cookie, x-id"`, []string{"private: duplicate field name X-Id"}, []string{"Set-Cookie", "X-Id"}},
+		{`no-cache="Set-Cookie, X-Id"`, nil, []string{"Set-Cookie", "X-Id"}},
+	}
+	for _, tt := range tests {
+		for _, strict := range []bool{false, true} {
+			var warnings []string
+			opts := &ParseOptions{Strict: strict, OnWarning: func(w Warning) { warnings = append(warnings, w.String()) }}
+			directive, err := ParseResponse(tt.value, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := tt.warnings
+			if !strict {
+				want = nil
+			}
+			if strings.Join(warnings, "|") != strings.Join(want, "|") {
+				t.Errorf("strict %v: ParseResponse(%q) warnings = %q, want %q", strict, tt.value, warnings, want)
+			}
+			fields := sortedFields(directive.NoCache)
+			if directive.PrivatePresent {
+				fields = sortedFields(directive.Private)
+			}
+			if strings.Join(fields, "|") != strings.Join(tt.fields, "|") {
+				t.Errorf("strict %v: ParseResponse(%q) fields = %q, want %q", strict, tt.value, fields, tt.fields)
+			}
+		}
+	}
+}
+
+func TestForceRevalidateAlways(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"no-cache", true},
+		{"no-cache, max-age=3600", true},
+		{`no-cache="Set-Cookie"`, false},
+		{`no-cache="Set-Cookie", max-age=3600`, false},
+		{`no-cache="Set-Cookie", max-age=0, must-revalidate`, true},
+		{"max-age=0, must-revalidate", true},
+		{"max-age=0", false},
+		{"max-age=60, must-revalidate", false},
+		{"must-revalidate", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := directive.ForceRevalidateAlways(); got != tt.want {
+			t.Errorf("%q: ForceRevalidateAlways() = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"sort"
+	"strings"
+)
+
+// ToFlat returns the directive as a map of directive names to values, which
+// maps directly onto gRPC metadata or any other multi-valued string map, so
+// that directives can cross service boundaries without being serialized to a
+// header and parsed again. The flat schema is:
+//
+//   - keys are lower-cased directive names, cache-extensions included;
+//   - boolean directives, and bare no-cache and private, have the single
+//     value "";
+//   - delta-seconds directives have their decimal value, as in "60";
+//   - no-cache and private with a field list have one value per field name,
+//     canonicalized and sorted;
+//   - cache-extensions have their unquoted value, or "" when bare, with one
+//     value per occurrence in the order of Extensions.
+//
+// Keys are plain directive names, so a caller sharing the map with other
+// metadata should prefix them. The directive is unchanged by a round trip
+// through FromFlat, except that extensions end up sorted by name and that a
+// cache-extension with an empty value, `x=` or `x=""`, comes back bare as `x`:
+// both map to "", and the flat schema cannot tell them apart.
+func (directive *ResponseCacheDirective) ToFlat() map[string][]string {
+	flat := make(map[string][]string)
+	for _, name := range responseDirectives {
+		value, present := directive.Get(name)
+		if !present {
+			continue
+		}
+		switch name {
+		case HeaderNoCache:
+			flat[name] = flatFieldList(directive.NoCache)
+		case HeaderPrivate:
+			flat[name] = flatFieldList(directive.Private)
+		default:
+			flat[name] = []string{value}
+		}
+	}
+	for _, ext := range directive.Extensions {
+		name := strings.ToLower(extensionName(ext))
+		value, _ := extensionValue([]string{ext}, name)
+		flat[name] = append(flat[name], value)
+	}
+	return flat
+}
+
+func flatFieldList(fields map[string]bool) []string {
+	if len(fields) == 0 {
+		return []string{""}
+	}
+	return sortedFields(fields)
+}
+
+// FromFlat builds a response directive from the flat schema of ToFlat. Keys are
+// matched case-insensitively, a key without values counts as a single "" and
+// a delta-seconds directive with several values keeps the last, as when
+// parsing. Values are validated like header values: `max-age` with "abc"
+// fails with ErrMaxAgeDeltaSeconds, and a value for a boolean directive fails
+// as it would in a header. Field names containing ',' are split, as in a
+// header.
+func FromFlat(flat map[string][]string) (*ResponseCacheDirective, error) {
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	directive := newResponseCacheDirective()
+	for _, key := range keys {
+		name := resolveAlias(strings.ToLower(key))
+		values := flat[key]
+		if len(values) == 0 {
+			values = []string{""}
+		}
+		if tokenRequireExtensionFields(name) {
+			values = []string{strings.Join(values, ",")}
+			if strings.Trim(values[0], ", \t") == "" {
+				values[0] = ""
+			}
+		}
+
+		for _, value := range values {
+			if err := setFlatValue(directive, name, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return directive, nil
+}
+
+// setFlatValue sets one directive from a flat value, recording unknown names as
+// cache-extensions.
+func setFlatValue(directive *ResponseCacheDirective, name, value string) error {
+	if value == "" {
+		if err := directive.setToken(name); err != errCacheExtension {
+			return err
+		}
+		directive.addExtension(name)
+		return nil
+	}
+	if err := directive.setPair(name, value); err != errCacheExtension {
+		return err
+	}
+	directive.addExtension(formatExtension(name, value))
+	return nil
+}
@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlatRoundTrip(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"public, max-age=60, s-maxage=30", "public, max-age=60, s-maxage=30"},
+		{`no-cache, private="Set-Cookie, X-Id"`, `private="Set-Cookie, X-Id", no-cache`},
+		{`no-cache="X-Id", no-store`, `no-cache="X-Id", no-store`},
+		{"zeta=1, alpha, beta=\"a b\"", "alpha, beta=\"a b\", zeta=1"},
+		{"ext=1, ext=2", "ext=1, ext=2"},
+
+		// An empty extension value is lost: it comes back bare.
+		{"ext", "ext"},
+		{"ext=", "ext"},
+		{`ext=""`, "ext"},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.header)
+		if err != nil {
+			t.Errorf("NewResponseCacheDirective(%q): %v", tt.header, err)
+			continue
+		}
+		flat := directive.ToFlat()
+		back, err := FromFlat(flat)
+		if err != nil {
+			t.Errorf("%q: FromFlat(%q): %v", tt.header, flat, err)
+			continue
+		}
+		if got := back.String(); got != tt.want {
+			t.Errorf("%q: round trip through %q = %q, want %q", tt.header, flat, got, tt.want)
+		}
+	}
+}
+
+func TestToFlat(t *testing.T) {
+	tests := []struct {
+		header string
+		want   map[string][]string
+	}{
+		{"", map[string][]string{}},
+		{"max-age=60, immutable", map[string][]string{"max-age": {"60"}, "immutable": {""}}},
+		{`private="x-id, Set-Cookie"`, map[string][]string{"private": {"Set-Cookie", "X-Id"}}},
+		{"no-cache", map[string][]string{"no-cache": {""}}},
+		{`Ext="a b", ext, ext=`, map[string][]string{"ext": {"a b", "", ""}}},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.header)
+		if err != nil {
+			t.Errorf("NewResponseCacheDirective(%q): %v", tt.header, err)
+			continue
+		}
+		if got := directive.ToFlat(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: ToFlat() = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,352 @@
+package cache
+
+import "math"
+
+// SkipRevalidationOnReload reports whether a client may reuse the response
+// without a conditional request even when the user reloads the page. This is
+// the optimization enabled by immutable (RFC 8246): the response will not
+// change during its freshness lifetime, so revalidating it is wasted work.
+//
+// It requires a present, non-zero max-age; immutable on its own is meaningless
+// and is reported by Validate.
+func (directive *ResponseCacheDirective) SkipRevalidationOnReload() bool {
+	return directive.Immutable && directive.MaxAge > 0
+}
+
+// ReloadForcesRevalidation reports whether a reload request must be answered
+// by revalidating the stored response, resolving the interaction between the
+// request's no-cache and max-age=0 and the response's immutable:
+//
+//   - no-cache, which browsers send on a forced reload, always wins: RFC 8246
+//     lets an explicit user override bypass immutable, and RFC 9111
+//     Section 5.2.1.4 forbids reuse without validation;
+//   - max-age=0, which browsers send on a normal reload, forces revalidation
+//     unless immutable applies (see SkipRevalidationOnReload), which is the
+//     case immutable was designed for;
+//   - any other request does not force revalidation by itself.
+func ReloadForcesRevalidation(req *RequestCacheDirective, resp *ResponseCacheDirective) bool {
+	if req.NoCache {
+		return true
+	}
+	if req.MaxAge == 0 {
+		return !resp.SkipRevalidationOnReload()
+	}
+	return false
+}
+
+// EffectivelyNoStoreForShared reports the common CDN misconfiguration of
+// max-age=0 without s-maxage (and without no-store).
+//
+// People often expect this to behave like no-store, but it does not: max-age=0
+// makes the response stale as soon as it is received, so a shared cache may
+// still store it and must revalidate it with the origin before every reuse
+// (RFC 9111 Section 4.2). no-store forbids storing it in the first place
+// (RFC 9111 Section 5.2.2.5). Use no-store if the response must never be kept,
+// or add s-maxage if shared caches are meant to hold it.
+func (directive *ResponseCacheDirective) EffectivelyNoStoreForShared() bool {
+	return directive.MaxAgePresent && directive.MaxAge == 0 && !directive.SMaxAgePresent && !directive.NoStore
+}
+
+// SharedTTL returns the freshness lifetime a shared cache should use for resp,
+// following RFC 9111 Section 4.2.1 precedence: s-maxage, then max-age, then
+// expiresLifetime. expiresLifetime is the lifetime derived by the caller from
+// Expires minus Date, or a heuristic lifetime; pass -1 when there is none.
+//
+// ok is false when a shared cache must not store resp at all (see Uncacheable)
+// or when no lifetime is available.
+func SharedTTL(resp *ResponseCacheDirective, expiresLifetime int32) (ttl int32, ok bool) {
+	if resp.Uncacheable(true) {
+		return 0, false
+	}
+
+	switch {
+	case resp.SMaxAgePresent:
+		return resp.SMaxAge, true
+	case resp.MaxAgePresent:
+		return resp.MaxAge, true
+	case expiresLifetime >= 0:
+		return expiresLifetime, true
+	}
+	return 0, false
+}
+
+// DownstreamMaxAge returns the max-age a shared cache should advertise to
+// downstream clients when it serves the response with remainingLifetime
+// seconds of freshness left, the TTL countdown of CDNs that cache on s-maxage
+// while browsers follow max-age: the smaller of the original max-age and
+// remainingLifetime, so that a browser copy does not outlive the cached one.
+// A negative remainingLifetime counts as 0.
+//
+// An immutable response keeps its original max-age, since its content never
+// changes while it is fresh (RFC 8246). Without max-age it returns -1: the
+// origin did not give browsers an explicit lifetime and none should be made
+// up.
+func (directive *ResponseCacheDirective) DownstreamMaxAge(remainingLifetime int32) int32 {
+	if !directive.MaxAgePresent {
+		return -1
+	}
+	if directive.Immutable {
+		return directive.MaxAge
+	}
+	if remainingLifetime < 0 {
+		remainingLifetime = 0
+	}
+	return minDeltaSeconds(directive.MaxAge, remainingLifetime)
+}
+
+// ForcesRevalidation reports whether the request asks caches not to reuse a
+// stored response without successfully revalidating it with the origin first,
+// the end-to-end reload of RFC 9111 Section 5.2.1.4.
+//
+// Unlike the response no-cache directive, the request form never carries a
+// field list: it always applies to the whole response.
+func (directive *RequestCacheDirective) ForcesRevalidation() bool {
+	return directive.NoCache
+}
+
+// EffectiveFreshnessWindow returns the greatest age, in seconds, at which a
+// response with the given freshness lifetime still satisfies the request's
+// max-age, min-fresh and max-stale directives (RFC 9111 Section 5.2.1).
+// A negative result means no age is acceptable.
+//
+// A response is fresh while its age is below its lifetime (RFC 9111
+// Section 4.2), so without directives the window is lifetime-1. Zero values
+// are meaningful and differ from absent (-1) ones:
+//   - min-fresh=0 requires the response to be fresh, same as no min-fresh;
+//   - max-stale=0 accepts a response that expired this very second, but
+//     nothing staler;
+//   - max-age=0 only accepts a response with an age of zero.
+//
+// A bare max-stale accepts a response of any age, so the window is then only
+// limited by max-age, if present.
+//
+// When both min-fresh and max-stale are present the stricter min-fresh wins,
+// as a response must satisfy every directive of the request.
+func (directive *RequestCacheDirective) EffectiveFreshnessWindow(lifetime int32) int32 {
+	window := addSeconds(lifetime, -1)
+	switch {
+	case directive.MinFresh > 0:
+		window = addSeconds(lifetime, -directive.MinFresh)
+	case directive.MinFresh == 0:
+	case directive.MaxStale >= 0:
+		window = addSeconds(lifetime, directive.MaxStale)
+	case directive.MaxStaleAny:
+		window = math.MaxInt32
+	}
+	if directive.MaxAge >= 0 && directive.MaxAge < window {
+		window = directive.MaxAge
+	}
+	return window
+}
+
+// SatisfiesRequestFreshness implements the request side of the reuse check
+// (RFC 9111 Section 4.2 and Section 5.2.1): may a stored response with the
+// given age and freshness lifetime be served for req without contacting the
+// origin?
+//
+// ok is true when it may. Otherwise revalidate tells whether the cache should
+// send a conditional request to the origin; it is false when req carries
+// only-if-cached, in which case the cache must answer 504 (Gateway Timeout)
+// instead (RFC 9111 Section 5.2.1.7).
+func SatisfiesRequestFreshness(req *RequestCacheDirective, responseAge, responseLifetime int32) (ok, revalidate bool) {
+	if !req.NoCache && responseAge <= req.EffectiveFreshnessWindow(responseLifetime) {
+		return true, false
+	}
+	return false, !req.OnlyIfCached
+}
+
+// CanServeStaleWhileRevalidate reports whether a private cache may serve the
+// response at the given age while it revalidates it in the background (RFC
+// 5861 Section 3). That is the case once the response is stale, i.e. its age
+// reached max-age (RFC 9111 Section 4.2), and for at most
+// stale-while-revalidate seconds after that.
+//
+// It is always false without both max-age and stale-while-revalidate, and
+// when no-store, no-cache or must-revalidate forbid serving stale content.
+// Shared caches must use CanServeStaleWhileRevalidateShared, which also
+// honours proxy-revalidate and s-maxage.
+func (directive *ResponseCacheDirective) CanServeStaleWhileRevalidate(ageSeconds int32) bool {
+	return directive.canServeStale(ageSeconds, false, directive.staleWhileRevalidate())
+}
+
+// CanServeStaleWhileRevalidateShared is CanServeStaleWhileRevalidate for a
+// shared cache: the response is stale once its age reached s-maxage, or
+// max-age without s-maxage, the lifetime SharedTTL uses, and it is always
+// false when proxy-revalidate or s-maxage, which implies proxy-revalidate
+// (RFC 9111 Section 5.2.2.10), forbid serving it stale.
+func (directive *ResponseCacheDirective) CanServeStaleWhileRevalidateShared(ageSeconds int32) bool {
+	return directive.canServeStale(ageSeconds, true, directive.staleWhileRevalidate())
+}
+
+// CanServeStaleOnErrorForRequest is CanServeStaleOnError taking the request's
+// own stale-if-error into account (RFC 5861 Section 4). When both the request
+// and the response carry stale-if-error the more restrictive window applies;
+// when only one does, its window applies. The request can never lift the
+// prohibitions of the response, such as must-revalidate.
+func CanServeStaleOnErrorForRequest(req *RequestCacheDirective, resp *ResponseCacheDirective, ageSeconds int32, shared bool) bool {
+	return resp.canServeStale(ageSeconds, shared, minDeltaSeconds(req.StaleIfError, resp.staleIfError()))
+}
+
+// CanServeStaleWhileRevalidateForRequest reports whether a cache of the given
+// type may serve the response at the given age while it revalidates it in the
+// background, honouring the request's stale-while-revalidate like
+// CanServeStaleOnErrorForRequest honours stale-if-error: the more restrictive
+// of both windows applies, and the response prohibitions always win.
+func CanServeStaleWhileRevalidateForRequest(req *RequestCacheDirective, resp *ResponseCacheDirective, ageSeconds int32, shared bool) bool {
+	return resp.canServeStale(ageSeconds, shared, minDeltaSeconds(req.StaleWhileRevalidate, resp.staleWhileRevalidate()))
+}
+
+// canServeStale reports whether the response may be served at the given age
+// by a cache of the given type, being stale by no more than window seconds.
+func (directive *ResponseCacheDirective) canServeStale(ageSeconds int32, shared bool, window int32) bool {
+	if directive.staleForbidden(shared) {
+		return false
+	}
+
+	lifetime := directive.lifetime(shared)
+	if lifetime < 0 || window < 0 {
+		return false
+	}
+	return ageSeconds >= lifetime && ageSeconds <= addSeconds(lifetime, window)
+}
+
+// lifetime returns the explicit freshness lifetime for the given cache type,
+// or -1 when the response has none. Shared caches prefer s-maxage over max-age,
+// private caches ignore s-maxage (RFC 9111 Section 4.2.1).
+func (directive *ResponseCacheDirective) lifetime(shared bool) int32 {
+	if shared && directive.SMaxAgePresent {
+		return directive.SMaxAge
+	}
+	return directive.maxAge()
+}
+
+// staleForbidden reports whether the directives prohibit the given cache type
+// from serving the response once it is stale (RFC 9111 Section 4.2.4).
+func (directive *ResponseCacheDirective) staleForbidden(shared bool) bool {
+	if directive.NoStore || directive.NoCachePresent || directive.MustRevalidate {
+		return true
+	}
+	return shared && (directive.ProxyRevalidate || directive.SMaxAgePresent)
+}
+
+// CanServeStaleOnError reports whether a cache may serve the response at the
+// given age when revalidating it failed because of an origin error (RFC 5861
+// Section 4): the response is stale, and stale by no more than stale-if-error
+// seconds.
+//
+// It is always false without an explicit lifetime and stale-if-error, and when
+// no-store, no-cache or must-revalidate forbid serving stale content. Shared
+// caches are further bound by proxy-revalidate and by s-maxage, which implies
+// proxy-revalidate (RFC 9111 Section 5.2.2.10).
+func (directive *ResponseCacheDirective) CanServeStaleOnError(ageSeconds int32, shared bool) bool {
+	return directive.canServeStale(ageSeconds, shared, directive.staleIfError())
+}
+
+// EffectiveMaxAge returns the tighter of the response's explicit freshness
+// lifetime for the given cache type (s-maxage or max-age, see SharedTTL) and
+// the request's max-age. Either side may be absent, in which case the other
+// one is returned; the result is -1 when neither is set.
+func EffectiveMaxAge(req *RequestCacheDirective, resp *ResponseCacheDirective, shared bool) int32 {
+	lifetime := resp.lifetime(shared)
+	if req.MaxAge >= 0 && (lifetime < 0 || req.MaxAge < lifetime) {
+		return req.MaxAge
+	}
+	return lifetime
+}
+
+// IsImmediatelyStale reports whether the response may be stored but is stale
+// from the moment it is received, because its explicit freshness lifetime for
+// the given cache type (s-maxage for shared caches, else max-age) is zero. An
+// explicit lifetime rules out heuristic freshness (RFC 9111 Section 4.2.2), so
+// every reuse requires revalidation.
+//
+// It is false for responses that may not be stored at all (see Uncacheable).
+// no-cache does not make a response stale: a response with no-cache and a
+// positive max-age is fresh, it just may not be reused without revalidation.
+func (directive *ResponseCacheDirective) IsImmediatelyStale(shared bool) bool {
+	return directive.lifetime(shared) == 0 && !directive.Uncacheable(shared)
+}
+
+// NeedsConditionalRequest reports whether a cache of the given type must send
+// a conditional request (If-None-Match, If-Modified-Since) to the origin before
+// reusing the stored response at the given age. That is the case when:
+//
+//   - a bare no-cache requires revalidation of every reuse (RFC 9111
+//     Section 5.2.2.4);
+//   - the response is stale, i.e. its age reached its explicit lifetime, see
+//     SharedTTL for the precedence. A response with max-age=0 is always stale.
+//     must-revalidate and proxy-revalidate make this a hard requirement
+//     (RFC 9111 Section 4.2.4); without them this package still treats stale
+//     responses as needing revalidation, see CanServeStaleOnError and
+//     CanServeStaleWhileRevalidate for the exceptions.
+//
+// It is false for a response that may not be stored at all (see Uncacheable),
+// as there is nothing to revalidate, and for a response without an explicit
+// lifetime, whose freshness depends on Expires or heuristics outside the scope
+// of this package.
+func (directive *ResponseCacheDirective) NeedsConditionalRequest(ageSeconds int32, shared bool) bool {
+	if directive.Uncacheable(shared) {
+		return false
+	}
+	if directive.NoCachePresent && len(directive.NoCache) == 0 {
+		return true
+	}
+	lifetime := directive.lifetime(shared)
+	return lifetime >= 0 && ageSeconds >= lifetime
+}
+
+// addSeconds returns a+b, saturated to the int32 range instead of wrapping
+// around. delta-seconds are clamped to math.MaxInt32 when parsed, so sums such
+// as max-age plus stale-while-revalidate can exceed it.
+func addSeconds(a, b int32) int32 {
+	sum := int64(a) + int64(b)
+	switch {
+	case sum > math.MaxInt32:
+		return math.MaxInt32
+	case sum < math.MinInt32:
+		return math.MinInt32
+	}
+	return int32(sum)
+}
+
+// Freshness is a snapshot of the directives governing the freshness of a
+// response, resolved for one cache type, see
+// (*ResponseCacheDirective).Freshness.
+type Freshness struct {
+	// Lifetime is the explicit freshness lifetime in seconds: s-maxage for
+	// shared caches when present, max-age otherwise. LifetimeOK is false when
+	// the response has none, and Lifetime is then -1.
+	Lifetime   int32
+	LifetimeOK bool
+
+	// MustRevalidateWhenStale reports whether the response may never be
+	// served stale without revalidation: must-revalidate, and for shared
+	// caches proxy-revalidate or s-maxage as well.
+	MustRevalidateWhenStale bool
+
+	// StaleWhileRevalidate and StaleIfError are the RFC 5861 windows in
+	// seconds, or -1 when absent or when the directives forbid serving the
+	// response stale to this cache type.
+	StaleWhileRevalidate, StaleIfError int32
+
+	// Immutable reports whether immutable applies, which requires a positive
+	// lifetime.
+	Immutable bool
+}
+
+// Freshness returns the freshness-governing values of the response resolved
+// for a cache of the given type, in a single call.
+func (directive *ResponseCacheDirective) Freshness(shared bool) Freshness {
+	f := Freshness{
+		Lifetime:                directive.lifetime(shared),
+		MustRevalidateWhenStale: directive.MustRevalidate || (shared && (directive.ProxyRevalidate || directive.SMaxAgePresent)),
+		StaleWhileRevalidate:    directive.staleWhileRevalidate(),
+		StaleIfError:            directive.staleIfError(),
+	}
+	f.LifetimeOK = f.Lifetime >= 0
+	f.Immutable = directive.Immutable && f.Lifetime > 0
+	if directive.staleForbidden(shared) {
+		f.StaleWhileRevalidate, f.StaleIfError = -1, -1
+	}
+	return f
+}
@@ -0,0 +1,411 @@
+package cache
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCanServeStaleWhileRevalidate(t *testing.T) {
+	tests := []struct {
+		value string
+		age   int32
+		want  bool
+	}{
+		{"max-age=60, stale-while-revalidate=30", 59, false},
+		{"max-age=60, stale-while-revalidate=30", 60, true},
+		{"max-age=60, stale-while-revalidate=30", 90, true},
+		{"max-age=60, stale-while-revalidate=30", 91, false},
+		{"max-age=0, stale-while-revalidate=30", 0, true},
+		{"max-age=60", 60, false},
+		{"stale-while-revalidate=30", 10, false},
+		{"max-age=60, stale-while-revalidate=30, must-revalidate", 60, false},
+		{"max-age=60, stale-while-revalidate=30, no-cache", 60, false},
+		{"max-age=60, stale-while-revalidate=30, proxy-revalidate", 60, true},
+		{"max-age=60, s-maxage=10, stale-while-revalidate=30", 60, true},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := directive.CanServeStaleWhileRevalidate(tt.age); got != tt.want {
+			t.Errorf("%q: CanServeStaleWhileRevalidate(%d) = %v, want %v", tt.value, tt.age, got, tt.want)
+		}
+	}
+}
+
+func TestCanServeStaleWhileRevalidateShared(t *testing.T) {
+	tests := []struct {
+		value string
+		age   int32
+		want  bool
+	}{
+		{"max-age=60, stale-while-revalidate=30", 60, true},
+		{"max-age=60, stale-while-revalidate=30", 90, true},
+		{"max-age=60, stale-while-revalidate=30", 91, false},
+		{"max-age=60, stale-while-revalidate=30, proxy-revalidate", 60, false},
+		{"max-age=60, s-maxage=10, stale-while-revalidate=30", 10, false},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := directive.CanServeStaleWhileRevalidateShared(tt.age); got != tt.want {
+			t.Errorf("%q: CanServeStaleWhileRevalidateShared(%d) = %v, want %v", tt.value, tt.age, got, tt.want)
+		}
+	}
+}
+
+func TestEffectiveFreshnessWindowZeroValues(t *testing.T) {
+	const lifetime = 100
+	tests := []struct {
+		value string
+		want  int32
+	}{
+		{"", 99},
+		{"min-fresh=0", 99},
+		{"min-fresh=10", 90},
+		{"max-stale=0", 100},
+		{"max-stale=10", 110},
+		{"max-stale", math.MaxInt32},
+		{"max-stale=0, min-fresh=0", 99},
+		{"max-age=0", 0},
+		{"max-age=0, max-stale", 0},
+	}
+	for _, tt := range tests {
+		directive, err := NewRequestCacheDirective(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := directive.EffectiveFreshnessWindow(lifetime); got != tt.want {
+			t.Errorf("%q: EffectiveFreshnessWindow(%d) = %d, want %d", tt.value, lifetime, got, tt.want)
+		}
+	}
+}
+
+func TestZeroDeltaSecondsSerialization(t *testing.T) {
+	for _, value := range []string{"max-age=0", "max-stale=0", "min-fresh=0", "max-stale", "max-stale=0, min-fresh=0"} {
+		directive, err := NewRequestCacheDirective(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := directive.String(); got != value {
+			t.Errorf("%q serializes as %q", value, got)
+		}
+	}
+
+	directive, err := NewRequestCacheDirective("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := directive.String(); got != "" {
+		t.Errorf("absent directives serialize as %q, want none", got)
+	}
+}
+
+func TestCanServeStaleOnError(t *testing.T) {
+	tests := []struct {
+		value  string
+		age    int32
+		shared bool
+		want   bool
+	}{
+		{"max-age=60, stale-if-error=30", 59, false, false},
+		{"max-age=60, stale-if-error=30", 60, false, true},
+		{"max-age=60, stale-if-error=30", 90, false, true},
+		{"max-age=60, stale-if-error=30", 91, false, false},
+		{"max-age=60", 60, false, false},
+		{"stale-if-error=30", 10, false, false},
+		{"max-age=60, stale-if-error=30, must-revalidate", 60, false, false},
+		{"max-age=60, stale-if-error=30, proxy-revalidate", 60, false, true},
+		{"max-age=60, stale-if-error=30, proxy-revalidate", 60, true, false},
+		{"max-age=60, s-maxage=10, stale-if-error=30", 10, true, false},
+		{"max-age=60, s-maxage=10, stale-if-error=30", 60, false, true},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := directive.CanServeStaleOnError(tt.age, tt.shared); got != tt.want {
+			t.Errorf("%q: CanServeStaleOnError(%d, %v) = %v, want %v", tt.value, tt.age, tt.shared, got, tt.want)
+		}
+	}
+}
+
+func TestIsImmediatelyStale(t *testing.T) {
+	tests := []struct {
+		value           string
+		private, shared bool
+	}{
+		{"max-age=0", true, true},
+		{"max-age=60", false, false},
+		{"", false, false},
+		{"s-maxage=0", false, true},
+		{"max-age=60, s-maxage=0", false, true},
+		{"max-age=0, s-maxage=60", true, false},
+		{"no-cache, max-age=60", false, false},
+		{"no-cache, max-age=0", true, true},
+		{"no-store, max-age=0", false, false},
+		{"private, max-age=0", true, false},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := directive.IsImmediatelyStale(false); got != tt.private {
+			t.Errorf("%q: IsImmediatelyStale(false) = %v, want %v", tt.value, got, tt.private)
+		}
+		if got := directive.IsImmediatelyStale(true); got != tt.shared {
+			t.Errorf("%q: IsImmediatelyStale(true) = %v, want %v", tt.value, got, tt.shared)
+		}
+	}
+}
+
+func TestNeedsConditionalRequest(t *testing.T) {
+	tests := []struct {
+		value  string
+		age    int32
+		shared bool
+		want   bool
+	}{
+		{"max-age=60", 59, false, false},
+		{"max-age=60", 60, false, true},
+		{"max-age=60", 61, false, true},
+		{"max-age=60, must-revalidate", 59, false, false},
+		{"max-age=60, must-revalidate", 60, false, true},
+		{"max-age=0", 0, false, true},
+		{"max-age=0", 0, true, true},
+		{"no-cache", 0, false, true},
+		{"no-cache, max-age=60", 0, false, true},
+		{`no-cache="Set-Cookie", max-age=60`, 0, false, false},
+		{`no-cache="Set-Cookie", max-age=60`, 60, false, true},
+		{"max-age=60, s-maxage=10", 10, false, false},
+		{"max-age=60, s-maxage=10", 9, true, false},
+		{"max-age=60, s-maxage=10", 10, true, true},
+		{"private, max-age=60", 60, true, false},
+		{"no-store, no-cache", 0, false, false},
+		{"", 1000, false, false},
+		{"max-age=2147483647", math.MaxInt32 - 1, false, false},
+		{"max-age=2147483647", math.MaxInt32, false, true},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := directive.NeedsConditionalRequest(tt.age, tt.shared); got != tt.want {
+			t.Errorf("%q: NeedsConditionalRequest(%d, %v) = %v, want %v", tt.value, tt.age, tt.shared, got, tt.want)
+		}
+	}
+}
+
+func TestAddSeconds(t *testing.T) {
+	tests := []struct {
+		a, b, want int32
+	}{
+		{1, 2, 3},
+		{math.MaxInt32, math.MaxInt32, math.MaxInt32},
+		{math.MaxInt32, 1, math.MaxInt32},
+		{math.MaxInt32, -1, math.MaxInt32 - 1},
+		{math.MinInt32, -1, math.MinInt32},
+	}
+	for _, tt := range tests {
+		if got := addSeconds(tt.a, tt.b); got != tt.want {
+			t.Errorf("addSeconds(%d, %d) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestStaleWindowsAtMaxInt32(t *testing.T) {
+	const huge = "max-age=2147483647, stale-while-revalidate=2147483647, stale-if-error=2147483647"
+	resp, err := NewResponseCacheDirective(huge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := NewRequestCacheDirective("max-stale=2147483647, stale-while-revalidate=2147483647, stale-if-error=2147483647")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const age = math.MaxInt32
+	for _, tt := range []struct {
+		name string
+		got  bool
+	}{
+		{"CanServeStaleWhileRevalidate", resp.CanServeStaleWhileRevalidate(age)},
+		{"CanServeStaleWhileRevalidateShared", resp.CanServeStaleWhileRevalidateShared(age)},
+		{"CanServeStaleOnError(private)", resp.CanServeStaleOnError(age, false)},
+		{"CanServeStaleOnError(shared)", resp.CanServeStaleOnError(age, true)},
+		{"CanServeStaleOnErrorForRequest", CanServeStaleOnErrorForRequest(req, resp, age, false)},
+		{"CanServeStaleWhileRevalidateForRequest", CanServeStaleWhileRevalidateForRequest(req, resp, age, true)},
+	} {
+		if !tt.got {
+			t.Errorf("%s at age %d = false for %q, want true", tt.name, age, huge)
+		}
+	}
+
+	if got := req.EffectiveFreshnessWindow(math.MaxInt32); got != math.MaxInt32 {
+		t.Errorf("EffectiveFreshnessWindow(MaxInt32) with max-stale=MaxInt32 = %d, want %d", got, int32(math.MaxInt32))
+	}
+	if ok, _ := SatisfiesRequestFreshness(req, age, math.MaxInt32); !ok {
+		t.Errorf("SatisfiesRequestFreshness(%d, MaxInt32) = false, want true", age)
+	}
+	minFresh, err := NewRequestCacheDirective("min-fresh=2147483647")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := minFresh.EffectiveFreshnessWindow(0); got != -math.MaxInt32 {
+		t.Errorf("EffectiveFreshnessWindow(0) with min-fresh=MaxInt32 = %d, want %d", got, -math.MaxInt32)
+	}
+}
+
+func TestFreshness(t *testing.T) {
+	tests := []struct {
+		value           string
+		private, shared Freshness
+	}{
+		{
+			"max-age=60, s-maxage=600, stale-while-revalidate=30, stale-if-error=300",
+			Freshness{Lifetime: 60, LifetimeOK: true, StaleWhileRevalidate: 30, StaleIfError: 300},
+			Freshness{Lifetime: 600, LifetimeOK: true, MustRevalidateWhenStale: true, StaleWhileRevalidate: -1, StaleIfError: -1},
+		},
+		{
+			"max-age=60, stale-while-revalidate=30, immutable",
+			Freshness{Lifetime: 60, LifetimeOK: true, StaleWhileRevalidate: 30, StaleIfError: -1, Immutable: true},
+			Freshness{Lifetime: 60, LifetimeOK: true, StaleWhileRevalidate: 30, StaleIfError: -1, Immutable: true},
+		},
+		{
+			"max-age=60, proxy-revalidate, stale-if-error=300",
+			Freshness{Lifetime: 60, LifetimeOK: true, StaleWhileRevalidate: -1, StaleIfError: 300},
+			Freshness{Lifetime: 60, LifetimeOK: true, MustRevalidateWhenStale: true, StaleWhileRevalidate: -1, StaleIfError: -1},
+		},
+		{
+			"s-maxage=0, immutable",
+			Freshness{Lifetime: -1, StaleWhileRevalidate: -1, StaleIfError: -1},
+			Freshness{Lifetime: 0, LifetimeOK: true, MustRevalidateWhenStale: true, StaleWhileRevalidate: -1, StaleIfError: -1},
+		},
+		{
+			"must-revalidate, max-age=0, stale-if-error=300",
+			Freshness{Lifetime: 0, LifetimeOK: true, MustRevalidateWhenStale: true, StaleWhileRevalidate: -1, StaleIfError: -1},
+			Freshness{Lifetime: 0, LifetimeOK: true, MustRevalidateWhenStale: true, StaleWhileRevalidate: -1, StaleIfError: -1},
+		},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := directive.Freshness(false); got != tt.private {
+			t.Errorf("%q: Freshness(false) = %+v, want %+v", tt.value, got, tt.private)
+		}
+		if got := directive.Freshness(true); got != tt.shared {
+			t.Errorf("%q: Freshness(true) = %+v, want %+v", tt.value, got, tt.shared)
+		}
+	}
+}
+
+func TestStaleWindowsForRequest(t *testing.T) {
+	tests := []struct {
+		request  string
+		response string
+		age      int32
+		want     bool
+	}{
+		{"stale-if-error=10", "max-age=60, stale-if-error=300", 70, true},
+		{"stale-if-error=10", "max-age=60, stale-if-error=300", 71, false},
+		{"stale-if-error=300", "max-age=60, stale-if-error=10", 70, true},
+		{"stale-if-error=300", "max-age=60, stale-if-error=10", 71, false},
+		{"", "max-age=60, stale-if-error=10", 70, true},
+		{"stale-if-error=300", "max-age=60", 360, true},
+		{"stale-if-error=300", "max-age=60", 361, false},
+		{"stale-if-error=0", "max-age=60, stale-if-error=300", 61, false},
+		{"stale-if-error=300", "max-age=60, must-revalidate", 61, false},
+		{"stale-if-error=300", "stale-if-error=300", 61, false},
+	}
+	for _, tt := range tests {
+		req, err := NewRequestCacheDirective(tt.request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := NewResponseCacheDirective(tt.response)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := CanServeStaleOnErrorForRequest(req, resp, tt.age, false); got != tt.want {
+			t.Errorf("%q, %q: CanServeStaleOnErrorForRequest(%d) = %v, want %v", tt.request, tt.response, tt.age, got, tt.want)
+		}
+
+		// stale-while-revalidate combines the same way.
+		swrReq := newRequestCacheDirective()
+		swrReq.StaleWhileRevalidate = req.StaleIfError
+		swrResp := *resp
+		swrResp.StaleWhileRevalidate, swrResp.StaleWhileRevalidatePresent = resp.StaleIfError, resp.StaleIfErrorPresent
+		swrResp.StaleIfError, swrResp.StaleIfErrorPresent = 0, false
+		if got := CanServeStaleWhileRevalidateForRequest(swrReq, &swrResp, tt.age, false); got != tt.want {
+			t.Errorf("%q, %q: CanServeStaleWhileRevalidateForRequest(%d) with stale-while-revalidate = %v, want %v", tt.request, tt.response, tt.age, got, tt.want)
+		}
+	}
+}
+
+func TestReloadForcesRevalidation(t *testing.T) {
+	tests := []struct {
+		request  string
+		response string
+		want     bool
+	}{
+		{"no-cache", "max-age=31536000, immutable", true},
+		{"no-cache", "max-age=60", true},
+		{"max-age=0", "max-age=31536000, immutable", false},
+		{"max-age=0", "max-age=60", true},
+		{"max-age=0", "immutable", true},
+		{"max-age=0", "max-age=0, immutable", true},
+		{"no-cache, max-age=0", "max-age=31536000, immutable", true},
+		{"", "max-age=60", false},
+		{"max-age=60", "max-age=31536000, immutable", false},
+	}
+	for _, tt := range tests {
+		req, err := NewRequestCacheDirective(tt.request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := NewResponseCacheDirective(tt.response)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ReloadForcesRevalidation(req, resp); got != tt.want {
+			t.Errorf("ReloadForcesRevalidation(%q, %q) = %v, want %v", tt.request, tt.response, got, tt.want)
+		}
+	}
+}
+
+func TestDownstreamMaxAge(t *testing.T) {
+	tests := []struct {
+		value     string
+		remaining int32
+		want      int32
+	}{
+		{"max-age=60, s-maxage=3600", 3600, 60},
+		{"max-age=60, s-maxage=3600", 61, 60},
+		{"max-age=60, s-maxage=3600", 60, 60},
+		{"max-age=60, s-maxage=3600", 30, 30},
+		{"max-age=60, s-maxage=3600", 0, 0},
+		{"max-age=60, s-maxage=3600", -10, 0},
+		{"max-age=3600", 1800, 1800},
+		{"max-age=0", 100, 0},
+		{"max-age=31536000, immutable", 10, 31536000},
+		{"s-maxage=3600", 1800, -1},
+		{"", 1800, -1},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := directive.DownstreamMaxAge(tt.remaining); got != tt.want {
+			t.Errorf("%q: DownstreamMaxAge(%d) = %d, want %d", tt.value, tt.remaining, got, tt.want)
+		}
+	}
+}
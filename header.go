@@ -19,3 +19,16 @@ const (
 	HeaderProxyRevalidate      = "proxy-revalidate"
 	HeaderStaleWhileRevalidate = "stale-while-revalidate"
 )
+
+// isKnownDirective reports whether name is one of the lower-cased directive
+// names above.
+func isKnownDirective(name string) bool {
+	switch name {
+	case HeaderMaxAge, HeaderNoCache, HeaderNoStore, HeaderMaxStale, HeaderMinFresh,
+		HeaderNoTransform, HeaderOnlyIfCached, HeaderPublic, HeaderPrivate,
+		HeaderSMaxAge, HeaderImmutable, HeaderStaleIfError, HeaderMustRevalidate,
+		HeaderProxyRevalidate, HeaderStaleWhileRevalidate:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"net/http"
+	"time"
+)
+
+// expiresInThePast is the Expires value written for responses HTTP/1.0 caches
+// must not reuse. Any date not after the Date header would do (RFC 9111
+// Section 5.3); the epoch is the customary choice.
+var expiresInThePast = time.Unix(0, 0).UTC().Format(http.TimeFormat)
+
+// HTTP10Headers derives the Expires and Pragma header values to send alongside
+// Cache-Control for caches that only understand HTTP/1.0. An empty result
+// means the header should not be sent. The derivation is:
+//
+//   - no-store, no-cache and private, bare or with a field list, which
+//     HTTP/1.0 cannot express: Expires is the epoch, so the response is
+//     already stale, and Pragma is "no-cache";
+//   - otherwise, with max-age: Expires is now plus max-age, formatted with
+//     http.TimeFormat, and there is no Pragma. max-age=0 yields now, a
+//     response that is stale on arrival;
+//   - otherwise there is no explicit lifetime and neither header is derived.
+//
+// s-maxage is ignored: Expires applies to every cache, and an HTTP/1.0 cache
+// that would honour s-maxage does not exist. Pragma is only defined for
+// requests (RFC 9111 Section 5.4), but legacy caches commonly honour it in
+// responses too.
+func (directive *ResponseCacheDirective) HTTP10Headers(now time.Time) (expires, pragma string) {
+	if directive.NoStore || directive.NoCachePresent || directive.PrivatePresent {
+		return expiresInThePast, "no-cache"
+	}
+	if !directive.MaxAgePresent {
+		return "", ""
+	}
+	return now.Add(time.Duration(directive.MaxAge) * time.Second).UTC().Format(http.TimeFormat), ""
+}
@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHTTP10Headers(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	tests := []struct {
+		header  string
+		expires string
+		pragma  string
+	}{
+		{"", "", ""},
+		{"public, s-maxage=60", "", ""},
+		{"max-age=60", "Fri, 01 Mar 2024 11:01:00 GMT", ""},
+		{"max-age=0", "Fri, 01 Mar 2024 11:00:00 GMT", ""},
+		{"public, max-age=3600, s-maxage=60", "Fri, 01 Mar 2024 12:00:00 GMT", ""},
+		{"no-store, max-age=60", "Thu, 01 Jan 1970 00:00:00 GMT", "no-cache"},
+		{"no-cache", "Thu, 01 Jan 1970 00:00:00 GMT", "no-cache"},
+		{`no-cache="Set-Cookie", max-age=60`, "Thu, 01 Jan 1970 00:00:00 GMT", "no-cache"},
+		{"private, max-age=60", "Thu, 01 Jan 1970 00:00:00 GMT", "no-cache"},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expires, pragma := directive.HTTP10Headers(now)
+		if expires != tt.expires || pragma != tt.pragma {
+			t.Errorf("%q: HTTP10Headers() = %q, %q, want %q, %q", tt.header, expires, pragma, tt.expires, tt.pragma)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package cache
+
+// CachingIntent is a high-level description of how a response should be
+// cached, see ForIntent.
+type CachingIntent int
+
+const (
+	// IntentNoStore expands to `no-store`: nothing may keep a copy, e.g. for
+	// responses carrying personal or one-time data.
+	IntentNoStore CachingIntent = iota
+
+	// IntentPrivateShortLived expands to `private, max-age=60`: only the
+	// user's browser may keep it, and only for a minute.
+	IntentPrivateShortLived
+
+	// IntentPublicImmutableAsset expands to
+	// `public, max-age=31536000, immutable`: fingerprinted static assets whose
+	// URL changes whenever their content does.
+	IntentPublicImmutableAsset
+
+	// IntentRevalidateAlways expands to `no-cache`: caches may store the
+	// response but must check with the origin before every reuse.
+	IntentRevalidateAlways
+)
+
+// ForIntent returns the directives implementing intent. It returns nil for an
+// unknown intent.
+func ForIntent(intent CachingIntent) *ResponseCacheDirective {
+	directive := newResponseCacheDirective()
+	switch intent {
+	case IntentNoStore:
+		directive.NoStore = true
+	case IntentPrivateShortLived:
+		directive.PrivatePresent = true
+		directive.MaxAge, directive.MaxAgePresent = 60, true
+	case IntentPublicImmutableAsset:
+		directive.Public = true
+		directive.MaxAge, directive.MaxAgePresent = 31536000, true
+		directive.Immutable = true
+	case IntentRevalidateAlways:
+		directive.NoCachePresent = true
+	default:
+		return nil
+	}
+	return directive
+}
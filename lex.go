@@ -6,6 +6,12 @@ func isWhiteSpace(c byte) bool {
 	return c == '\t' || c == ' '
 }
 
+// isExtendedWhiteSpace reports whether c is ASCII whitespace beyond OWS that
+// is not a line break: VT and FF.
+func isExtendedWhiteSpace(c byte) bool {
+	return c == '\v' || c == '\f'
+}
+
 func isSeparator(c byte) bool {
 	switch c {
 	case '(', ')', '<', '>', '@', ',', ';', ':', '\\', '"', '/', '[', ']', '?', '=', '{', '}', ' ', '\t':
@@ -20,6 +26,32 @@ func isChar(c byte) bool { return c <= 127 } //nolint
 
 func isAnyText(c byte) bool { return !isCtl(c) }
 
-func isQdText(c byte) bool { return isAnyText(c) && c != '"' }
+// isQdText is qdtext as defined by RFC 9110 Section 5.6.4, including obs-text:
+// HTAB, SP and every visible byte except DQUOTE and backslash, plus 0x80-0xFF.
+func isQdText(c byte) bool {
+	return c == '\t' || c == ' ' || (isVChar(c) && c != '"' && c != '\\') || isObsText(c)
+}
 
 func isToken(c byte) bool { return isChar(c) && !isCtl(c) && !isSeparator(c) }
+
+func isVChar(c byte) bool { return c > 32 && c < 127 }
+
+func isObsText(c byte) bool { return c >= 128 }
+
+// isQuotedPairText reports whether c may follow a backslash in a quoted-pair.
+func isQuotedPairText(c byte) bool {
+	return c == '\t' || c == ' ' || isVChar(c) || isObsText(c)
+}
+
+// isTokenString reports whether s is a non-empty token.
+func isTokenString(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isToken(s[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package cache
+
+import "strings"
+
+// Severity ranks how much a LintFinding matters.
+type Severity int
+
+const (
+	// SeverityInfo marks a harmless but questionable header.
+	SeverityInfo Severity = iota
+	// SeverityWarning marks a header that probably does not do what was intended.
+	SeverityWarning
+	// SeverityError marks a header whose directives contradict each other.
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return "unknown"
+}
+
+// Lint finding codes.
+const (
+	LintImmutableWithoutMaxAge = "immutable-without-max-age"
+	LintSMaxAgeMisspelling     = "s-max-age-misspelling"
+	LintMaxAgeZero             = "max-age-zero"
+	LintPublicAndPrivate       = "public-and-private"
+)
+
+// LintFinding is a best-practice violation reported by Lint.
+type LintFinding struct {
+	// Code identifies the check, one of the Lint* constants.
+	Code string
+
+	Severity Severity
+
+	// Message explains the problem and how to fix it.
+	Message string
+}
+
+func (f LintFinding) String() string {
+	return f.Severity.String() + ": " + f.Code + ": " + f.Message
+}
+
+// Lint reports best-practice violations in resp. Unlike Validate, whose
+// findings are plain errors, Lint is advisory: each finding explains a likely
+// mistake and is safe to ignore when the header is intentional. Findings are
+// returned in the order of the checks below.
+//
+//   - immutable-without-max-age (warning): immutable has no effect without a
+//     non-zero max-age.
+//   - s-max-age-misspelling (warning): `s-max-age` is not `s-maxage` and is
+//     ignored by caches.
+//   - max-age-zero (info): max-age=0 without no-cache; no-cache states the
+//     intent to always revalidate more clearly.
+//   - public-and-private (error): public and private contradict each other.
+func Lint(resp *ResponseCacheDirective) []LintFinding {
+	var findings []LintFinding
+	add := func(code string, severity Severity, message string) {
+		findings = append(findings, LintFinding{Code: code, Severity: severity, Message: message})
+	}
+
+	if resp.Immutable && resp.MaxAge <= 0 {
+		add(LintImmutableWithoutMaxAge, SeverityWarning,
+			"immutable has no effect without a non-zero max-age")
+	}
+	for _, ext := range resp.Extensions {
+		if strings.EqualFold(extensionName(ext), "s-max-age") {
+			add(LintSMaxAgeMisspelling, SeverityWarning,
+				"`s-max-age` is ignored by caches, did you mean `s-maxage`?")
+			break
+		}
+	}
+	if resp.MaxAgePresent && resp.MaxAge == 0 && !resp.NoCachePresent && !resp.NoStore {
+		add(LintMaxAgeZero, SeverityInfo,
+			"max-age=0 without no-cache, did you mean no-cache?")
+	}
+	if resp.Public && resp.PrivatePresent {
+		add(LintPublicAndPrivate, SeverityError,
+			"public and private are both set, caches will treat the response as private")
+	}
+	return findings
+}
@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		header string
+		codes  []string
+	}{
+		{"public, max-age=60", nil},
+		{"max-age=31536000, immutable", nil},
+		{"immutable", []string{LintImmutableWithoutMaxAge}},
+		{"max-age=0, immutable", []string{LintImmutableWithoutMaxAge, LintMaxAgeZero}},
+		{"s-max-age=60", []string{LintSMaxAgeMisspelling}},
+		{"S-Max-Age=60, s-max-age=120", []string{LintSMaxAgeMisspelling}},
+		{"max-age=0", []string{LintMaxAgeZero}},
+		{"max-age=0, no-cache", nil},
+		{"max-age=0, no-store", nil},
+		{"public, private", []string{LintPublicAndPrivate}},
+		{`public, private="Set-Cookie"`, []string{LintPublicAndPrivate}},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var codes []string
+		for _, finding := range Lint(directive) {
+			codes = append(codes, finding.Code)
+		}
+		if !reflect.DeepEqual(codes, tt.codes) {
+			t.Errorf("Lint(%q) codes = %q, want %q", tt.header, codes, tt.codes)
+		}
+	}
+}
+
+func TestLintFindingString(t *testing.T) {
+	directive, err := NewResponseCacheDirective("public, private")
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := Lint(directive)
+	if len(findings) != 1 {
+		t.Fatalf("Lint(%q) = %v, want one finding", "public, private", findings)
+	}
+	want := "error: public-and-private: public and private are both set, caches will treat the response as private"
+	if got := findings[0].String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,85 @@
+package cache
+
+import "context"
+
+// ParseAndMerge parses value and overlays it onto the directive. Directives
+// present in value replace the current ones (last wins), directives absent
+// from value are left untouched and extensions are appended. Since boolean
+// directives can only be switched on by a header, a merge never clears them.
+//
+// The directive is only modified when value parses successfully.
+func (directive *RequestCacheDirective) ParseAndMerge(value string) error {
+	merged := *directive
+	merged.Extensions = append([]string(nil), directive.Extensions...)
+	if err := parseCacheControlv(context.Background(), &merged, value, nil); err != nil {
+		return err
+	}
+	*directive = merged
+	return nil
+}
+
+// CombineRequests combines the request directives of a client and of an
+// intermediary that adds its own preferences, such as a gateway in front of a
+// cache. The result is the most conservative request satisfying both:
+//
+//   - no-cache, no-store, no-transform and only-if-cached apply when either
+//     side sends them;
+//   - the smaller max-age, stale-if-error and stale-while-revalidate and the
+//     larger min-fresh win;
+//   - max-stale is only kept when both sides accept stale responses, with the
+//     smaller limit; a bare max-stale accepts any staleness and loses to a
+//     valued one;
+//   - extensions from a, then those from b that a does not already carry.
+//
+// Neither a nor b is modified.
+func CombineRequests(a, b *RequestCacheDirective) *RequestCacheDirective {
+	combined := newRequestCacheDirective()
+	combined.NoCache = a.NoCache || b.NoCache
+	combined.NoStore = a.NoStore || b.NoStore
+	combined.NoTransform = a.NoTransform || b.NoTransform
+	combined.OnlyIfCached = a.OnlyIfCached || b.OnlyIfCached
+	combined.MaxAge = minDeltaSeconds(a.MaxAge, b.MaxAge)
+	combined.StaleIfError = minDeltaSeconds(a.StaleIfError, b.StaleIfError)
+	combined.StaleWhileRevalidate = minDeltaSeconds(a.StaleWhileRevalidate, b.StaleWhileRevalidate)
+	if a.MinFresh > b.MinFresh {
+		combined.MinFresh = a.MinFresh
+	} else {
+		combined.MinFresh = b.MinFresh
+	}
+
+	aStale := a.MaxStale >= 0 || a.MaxStaleAny
+	bStale := b.MaxStale >= 0 || b.MaxStaleAny
+	if aStale && bStale {
+		combined.MaxStale = minDeltaSeconds(a.MaxStale, b.MaxStale)
+		combined.MaxStaleAny = combined.MaxStale < 0
+	}
+
+	combined.Extensions = append([]string(nil), a.Extensions...)
+	for _, ext := range b.Extensions {
+		if !containsString(combined.Extensions, ext) {
+			combined.Extensions = append(combined.Extensions, ext)
+		}
+	}
+	if len(combined.Extensions) == 0 {
+		combined.Extensions = nil
+	}
+	return combined
+}
+
+// minDeltaSeconds returns the smaller of two delta-seconds values, ignoring an
+// absent (-1) one.
+func minDeltaSeconds(a, b int32) int32 {
+	if a < 0 || (b >= 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+func containsString(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
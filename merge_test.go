@@ -0,0 +1,87 @@
+package cache
+
+import "testing"
+
+func TestParseAndMerge(t *testing.T) {
+	tests := []struct {
+		name        string
+		base, value string
+		want        string
+	}{
+		{"overlay max-age", "max-age=60, no-store", "max-age=10", "max-age=10, no-store"},
+		{"keep unset directives", "max-age=60, min-fresh=5", "no-cache", "max-age=60, min-fresh=5, no-cache"},
+		{"toggle no-cache on", "max-age=60", "no-cache", "max-age=60, no-cache"},
+		{"no-cache stays on", "no-cache", "max-age=0", "max-age=0, no-cache"},
+		{"last wins within value", "max-age=60", "max-age=1, max-age=2", "max-age=2"},
+		{"append extensions", "ext-a=1", "ext-b=2", "ext-a=1, ext-b=2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			directive, err := NewRequestCacheDirective(tt.base)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := directive.ParseAndMerge(tt.value); err != nil {
+				t.Fatal(err)
+			}
+			if got := directive.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAndMergeLeavesDirectiveOnError(t *testing.T) {
+	directive, err := NewRequestCacheDirective("max-age=60, ext=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := directive.ParseAndMerge("ext=2, max-age=abc"); err == nil {
+		t.Fatal("ParseAndMerge accepted max-age=abc")
+	}
+	if got, want := directive.String(), "max-age=60, ext=1"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestCombineRequests(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"empty", "", "", ""},
+		{"flags from either side", "no-cache", "no-store, only-if-cached", "no-cache, no-store, only-if-cached"},
+		{"smaller max-age", "max-age=60", "max-age=10", "max-age=10"},
+		{"max-age from one side", "", "max-age=0", "max-age=0"},
+		{"smaller stale windows", "stale-if-error=60, stale-while-revalidate=5", "stale-if-error=30", "stale-while-revalidate=5, stale-if-error=30"},
+		{"larger min-fresh", "min-fresh=5", "min-fresh=20", "min-fresh=20"},
+		{"max-stale needs both sides", "max-stale=30", "", ""},
+		{"smaller max-stale", "max-stale=30", "max-stale=10", "max-stale=10"},
+		{"valued max-stale beats bare", "max-stale", "max-stale=10", "max-stale=10"},
+		{"bare max-stale on both sides", "max-stale", "max-stale", "max-stale"},
+		{"extensions deduplicated", "ext-a=1, ext-b", "ext-b, ext-a=2", "ext-a=1, ext-b, ext-a=2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewRequestCacheDirective(tt.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := NewRequestCacheDirective(tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			before := a.String() + "|" + b.String()
+			if got := CombineRequests(a, b).String(); got != tt.want {
+				t.Errorf("CombineRequests(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+			}
+			if got := CombineRequests(b, a).String(); len(a.Extensions)+len(b.Extensions) == 0 && got != tt.want {
+				t.Errorf("CombineRequests(%q, %q) = %q, want %q", tt.b, tt.a, got, tt.want)
+			}
+			if after := a.String() + "|" + b.String(); after != before {
+				t.Errorf("CombineRequests modified its arguments: %q, want %q", after, before)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package cache
+
+// Minimize returns a copy of the directive without the directives another one
+// makes redundant, to shorten a header and make its intent obvious. The
+// domination rules are:
+//
+//   - no-store forbids storing the response at all (RFC 9111 Section
+//     5.2.2.5), so it drops every other caching directive: public, private,
+//     no-cache, max-age, s-maxage, must-revalidate, proxy-revalidate,
+//     immutable, stale-while-revalidate and stale-if-error;
+//   - a bare no-cache requires validation before every reuse (Section
+//     5.2.2.4), so it drops must-revalidate, proxy-revalidate, immutable,
+//     stale-while-revalidate and stale-if-error, which only concern reusing
+//     the response without validation;
+//   - must-revalidate drops proxy-revalidate, its shared-cache-only
+//     counterpart (Section 5.2.2.8);
+//   - a bare private drops public, as it forbids what public allows;
+//   - immutable without a non-zero max-age has no effect, see Validate.
+//
+// max-age and s-maxage are kept next to a bare no-cache for caches that do not
+// understand it, and must-revalidate with max-age, including max-age=0, is
+// kept as it still forbids serving the response stale. no-transform and
+// cache-extensions are always kept. So `no-store, max-age=0, no-cache`
+// minimizes to `no-store`.
+//
+// The directive itself is not modified.
+func (directive *ResponseCacheDirective) Minimize() *ResponseCacheDirective {
+	minimized := newResponseCacheDirective()
+	minimized.NoTransform = directive.NoTransform
+	minimized.Extensions = append([]string(nil), directive.Extensions...)
+	minimized.Comment = directive.Comment
+	if directive.NoStore {
+		minimized.NoStore = true
+		return minimized
+	}
+
+	minimized.Public = directive.Public && !(directive.PrivatePresent && len(directive.Private) == 0)
+	minimized.PrivatePresent = directive.PrivatePresent
+	minimized.Private, minimized.PrivateRaw = copyFieldList(directive.Private, directive.PrivateRaw)
+	minimized.NoCachePresent = directive.NoCachePresent
+	minimized.NoCache, minimized.NoCacheRaw = copyFieldList(directive.NoCache, directive.NoCacheRaw)
+	minimized.MaxAge, minimized.MaxAgePresent = directive.MaxAge, directive.MaxAgePresent
+	minimized.SMaxAge, minimized.SMaxAgePresent = directive.SMaxAge, directive.SMaxAgePresent
+
+	if directive.NoCachePresent && len(directive.NoCache) == 0 {
+		return minimized
+	}
+
+	minimized.MustRevalidate = directive.MustRevalidate
+	minimized.ProxyRevalidate = directive.ProxyRevalidate && !directive.MustRevalidate
+	minimized.Immutable = directive.Immutable && directive.MaxAge > 0
+	minimized.StaleWhileRevalidate, minimized.StaleWhileRevalidatePresent = directive.StaleWhileRevalidate, directive.StaleWhileRevalidatePresent
+	minimized.StaleIfError, minimized.StaleIfErrorPresent = directive.StaleIfError, directive.StaleIfErrorPresent
+	return minimized
+}
+
+// copyFieldList copies a field list map and its raw names.
+func copyFieldList(fields map[string]bool, raw []string) (map[string]bool, []string) {
+	var copied map[string]bool
+	if fields != nil {
+		copied = make(map[string]bool, len(fields))
+		for name, v := range fields {
+			copied[name] = v
+		}
+	}
+	return copied, append([]string(nil), raw...)
+}
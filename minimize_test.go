@@ -0,0 +1,35 @@
+package cache
+
+import "testing"
+
+func TestMinimize(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"no-store, max-age=0", "no-store"},
+		{"no-store, max-age=0, no-cache", "no-store"},
+		{"no-store, public, must-revalidate, no-transform, ext=1", "no-store, no-transform, ext=1"},
+		{"no-cache, max-age=60, must-revalidate, stale-if-error=30", "no-cache, max-age=60"},
+		{`no-cache="Set-Cookie", max-age=60, must-revalidate`, `no-cache="Set-Cookie", max-age=60, must-revalidate`},
+		{"max-age=0, must-revalidate", "max-age=0, must-revalidate"},
+		{"max-age=60, must-revalidate, proxy-revalidate", "max-age=60, must-revalidate"},
+		{"public, private, max-age=60", "private, max-age=60"},
+		{`public, private="Set-Cookie"`, `public, private="Set-Cookie"`},
+		{"immutable", ""},
+		{"max-age=0, immutable", "max-age=0"},
+		{"max-age=31536000, immutable", "max-age=31536000, immutable"},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := directive.Minimize().String(); got != tt.want {
+			t.Errorf("%q: Minimize() = %q, want %q", tt.value, got, tt.want)
+		}
+		if got, _ := NewResponseCacheDirective(tt.value); !got.Equal(directive) {
+			t.Errorf("%q: Minimize() modified the directive to %q", tt.value, directive.String())
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// FromHeader parses every Cache-Control field line in h as one response
+// directive. Multiple field lines are combined by joining them with commas,
+// which RFC 9110 Section 5.3 defines as equivalent to a single line.
+func FromHeader(h http.Header) (*ResponseCacheDirective, error) {
+	return NewResponseCacheDirective(strings.Join(h.Values("Cache-Control"), ", "))
+}
+
+var (
+	ErrCacheControlInTrailer = errors.New("Cache-Control sent as a trailer field")
+)
+
+// FromResponseHeadersOnly parses the Cache-Control header of r, deliberately
+// ignoring r.Trailer.
+//
+// Trailer fields arrive after the content and are not covered by whatever
+// checks a proxy applied to the header section, so a cache that let them
+// override caching policy could be tricked into storing or serving responses
+// it should not. RFC 9110 Section 6.5.1 only allows a field in the trailers
+// when its definition permits it, which Cache-Control's does not. When
+// Cache-Control is found only in the trailers, including trailers that are
+// merely announced, ErrCacheControlInTrailer is returned so the caller can
+// treat the response as suspicious.
+func FromResponseHeadersOnly(r *http.Response) (*ResponseCacheDirective, error) {
+	if len(r.Header.Values("Cache-Control")) == 0 {
+		if _, ok := r.Trailer["Cache-Control"]; ok {
+			return nil, ErrCacheControlInTrailer
+		}
+	}
+	return FromHeader(r.Header)
+}
+
+// ValueError reports which of several Cache-Control values failed to parse.
+type ValueError struct {
+	// Index is the position of the value in the slice given to ParseAllResponses.
+	Index int
+
+	Err error
+}
+
+func (e *ValueError) Error() string {
+	return fmt.Sprintf("Cache-Control value %d: %v", e.Index, e.Err)
+}
+
+func (e *ValueError) Unwrap() error { return e.Err }
+
+// ParseAllResponses parses each value on its own and returns one directive per
+// value, e.g. to see what each hop or middleware contributed. This differs from
+// FromHeader, which merges all values into a single directive the way a cache
+// interprets them. Parsing stops at the first malformed value and the returned
+// *ValueError identifies it.
+func ParseAllResponses(values []string) ([]*ResponseCacheDirective, error) {
+	directives := make([]*ResponseCacheDirective, 0, len(values))
+	for i, value := range values {
+		directive, err := NewResponseCacheDirective(value)
+		if err != nil {
+			return nil, &ValueError{Index: i, Err: err}
+		}
+		directives = append(directives, directive)
+	}
+	return directives, nil
+}
+
+// Conflict describes two Cache-Control field lines whose directives disagree.
+type Conflict struct {
+	// Lines are the indexes of the conflicting field lines, in the order
+	// returned by http.Header.Values.
+	Lines [2]int
+
+	// Directives are the conflicting directive names, in the same order as Lines.
+	Directives [2]string
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s (line %d) conflicts with %s (line %d)",
+		c.Directives[0], c.Lines[0], c.Directives[1], c.Lines[1])
+}
+
+// HeaderDirectives is the result of FromHeaderWithConflicts.
+type HeaderDirectives struct {
+	// Merged is the directive FromHeader would have returned.
+	Merged *ResponseCacheDirective
+
+	// Lines holds the directive parsed from each field line on its own.
+	Lines []*ResponseCacheDirective
+
+	// Conflicts lists the disagreements found between field lines.
+	Conflicts []Conflict
+}
+
+// FromHeaderWithConflicts behaves like FromHeader but also parses each field
+// line separately and reports directives that disagree across lines. This is
+// usually the sign of several middlewares each setting Cache-Control.
+//
+// The following are reported as conflicts:
+//   - the same delta-seconds directive with different values;
+//   - public on one line and private on another;
+//   - no-store or bare no-cache on one line and a positive max-age or
+//     s-maxage on another.
+func FromHeaderWithConflicts(h http.Header) (*HeaderDirectives, error) {
+	values := h.Values("Cache-Control")
+
+	lines, err := ParseAllResponses(values)
+	if err != nil {
+		return nil, err
+	}
+	result := &HeaderDirectives{Lines: lines}
+
+	merged, err := NewResponseCacheDirective(strings.Join(values, ", "))
+	if err != nil {
+		return nil, err
+	}
+	result.Merged = merged
+
+	for i := range result.Lines {
+		for j := i + 1; j < len(result.Lines); j++ {
+			result.Conflicts = appendConflicts(result.Conflicts, i, j, result.Lines[i], result.Lines[j])
+		}
+	}
+	return result, nil
+}
+
+func appendConflicts(conflicts []Conflict, i, j int, a, b *ResponseCacheDirective) []Conflict {
+	add := func(first, second string) {
+		conflicts = append(conflicts, Conflict{Lines: [2]int{i, j}, Directives: [2]string{first, second}})
+	}
+
+	deltas := []struct {
+		name string
+		a, b int32
+	}{
+		{HeaderMaxAge, a.maxAge(), b.maxAge()},
+		{HeaderSMaxAge, a.sMaxAge(), b.sMaxAge()},
+		{HeaderStaleIfError, a.staleIfError(), b.staleIfError()},
+		{HeaderStaleWhileRevalidate, a.staleWhileRevalidate(), b.staleWhileRevalidate()},
+	}
+	for _, d := range deltas {
+		if d.a >= 0 && d.b >= 0 && d.a != d.b {
+			add(d.name, d.name)
+		}
+	}
+
+	if a.Public && b.PrivatePresent {
+		add(HeaderPublic, HeaderPrivate)
+	}
+	if a.PrivatePresent && b.Public {
+		add(HeaderPrivate, HeaderPublic)
+	}
+
+	for _, name := range forbidsFreshness(a) {
+		for _, other := range grantsFreshness(b) {
+			add(name, other)
+		}
+	}
+	for _, name := range grantsFreshness(a) {
+		for _, other := range forbidsFreshness(b) {
+			add(name, other)
+		}
+	}
+	return conflicts
+}
+
+func forbidsFreshness(d *ResponseCacheDirective) []string {
+	var names []string
+	if d.NoStore {
+		names = append(names, HeaderNoStore)
+	}
+	if d.NoCachePresent && len(d.NoCache) == 0 {
+		names = append(names, HeaderNoCache)
+	}
+	return names
+}
+
+func grantsFreshness(d *ResponseCacheDirective) []string {
+	var names []string
+	if d.MaxAge > 0 {
+		names = append(names, HeaderMaxAge)
+	}
+	if d.SMaxAge > 0 {
+		names = append(names, HeaderSMaxAge)
+	}
+	return names
+}
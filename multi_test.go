@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestFromHeaderWithConflicts(t *testing.T) {
+	tests := []struct {
+		lines     []string
+		merged    string
+		conflicts []Conflict
+	}{
+		{nil, "", nil},
+		{[]string{"public, max-age=60"}, "public, max-age=60", nil},
+		{[]string{"public", "max-age=60"}, "public, max-age=60", nil},
+		{[]string{"max-age=60", "max-age=60"}, "max-age=60", nil},
+		{[]string{"max-age=60", "s-maxage=30"}, "max-age=60, s-maxage=30", nil},
+		{
+			[]string{"max-age=60", "max-age=120"}, "max-age=120",
+			[]Conflict{{Lines: [2]int{0, 1}, Directives: [2]string{HeaderMaxAge, HeaderMaxAge}}},
+		},
+		{
+			[]string{"stale-if-error=0", "stale-if-error=10"}, "stale-if-error=10",
+			[]Conflict{{Lines: [2]int{0, 1}, Directives: [2]string{HeaderStaleIfError, HeaderStaleIfError}}},
+		},
+		{
+			[]string{"public", "private"}, "public, private",
+			[]Conflict{{Lines: [2]int{0, 1}, Directives: [2]string{HeaderPublic, HeaderPrivate}}},
+		},
+		{
+			[]string{`private="Set-Cookie"`, "public"}, `public, private="Set-Cookie"`,
+			[]Conflict{{Lines: [2]int{0, 1}, Directives: [2]string{HeaderPrivate, HeaderPublic}}},
+		},
+		{
+			[]string{"max-age=60, s-maxage=60", "no-store"}, "no-store, max-age=60, s-maxage=60",
+			[]Conflict{
+				{Lines: [2]int{0, 1}, Directives: [2]string{HeaderMaxAge, HeaderNoStore}},
+				{Lines: [2]int{0, 1}, Directives: [2]string{HeaderSMaxAge, HeaderNoStore}},
+			},
+		},
+		{[]string{"no-cache", "max-age=0"}, "no-cache, max-age=0", nil},
+		{[]string{`no-cache="Set-Cookie"`, "max-age=60"}, `no-cache="Set-Cookie", max-age=60`, nil},
+		{
+			[]string{"no-cache", "public", "max-age=60"}, "public, no-cache, max-age=60",
+			[]Conflict{{Lines: [2]int{0, 2}, Directives: [2]string{HeaderNoCache, HeaderMaxAge}}},
+		},
+	}
+	for _, tt := range tests {
+		h := http.Header{"Cache-Control": tt.lines}
+		got, err := FromHeaderWithConflicts(h)
+		if err != nil {
+			t.Errorf("FromHeaderWithConflicts(%q): %v", tt.lines, err)
+			continue
+		}
+		if merged := got.Merged.String(); merged != tt.merged {
+			t.Errorf("FromHeaderWithConflicts(%q).Merged = %q, want %q", tt.lines, merged, tt.merged)
+		}
+		if len(got.Lines) != len(tt.lines) {
+			t.Errorf("FromHeaderWithConflicts(%q).Lines has %d entries, want %d", tt.lines, len(got.Lines), len(tt.lines))
+		}
+		if !reflect.DeepEqual(got.Conflicts, tt.conflicts) {
+			t.Errorf("FromHeaderWithConflicts(%q).Conflicts = %v, want %v", tt.lines, got.Conflicts, tt.conflicts)
+		}
+	}
+
+	h := http.Header{"Cache-Control": {"public", "max-age=abc"}}
+	var valueErr *ValueError
+	if _, err := FromHeaderWithConflicts(h); !errors.As(err, &valueErr) || valueErr.Index != 1 || !errors.Is(err, ErrMaxAgeDeltaSeconds) {
+		t.Errorf("FromHeaderWithConflicts(%q) = %v, want a ValueError for line 1", h.Values("Cache-Control"), err)
+	}
+}
+
+func TestConflictString(t *testing.T) {
+	c := Conflict{Lines: [2]int{0, 2}, Directives: [2]string{HeaderNoStore, HeaderMaxAge}}
+	if got, want := c.String(), "no-store (line 0) conflicts with max-age (line 2)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestFromResponseHeadersOnly(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  http.Header
+		trailer http.Header
+		want    string
+		err     error
+	}{
+		{"header only", http.Header{"Cache-Control": {"max-age=60"}}, nil, "max-age=60", nil},
+		{"neither", http.Header{}, http.Header{}, "", nil},
+		{"trailer only", http.Header{}, http.Header{"Cache-Control": {"max-age=60"}}, "", ErrCacheControlInTrailer},
+		{"announced trailer", http.Header{}, http.Header{"Cache-Control": nil}, "", ErrCacheControlInTrailer},
+		{"trailer ignored", http.Header{"Cache-Control": {"no-store"}}, http.Header{"Cache-Control": {"public, max-age=60"}}, "no-store", nil},
+		{"other trailer", http.Header{"Cache-Control": {"private"}}, http.Header{"Expires": {"0"}}, "private", nil},
+	}
+	for _, tt := range tests {
+		resp := &http.Response{Header: tt.header, Trailer: tt.trailer}
+		got, err := FromResponseHeadersOnly(resp)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("%s: FromResponseHeadersOnly = %v, %v, want %v", tt.name, got, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: FromResponseHeadersOnly: %v", tt.name, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("%s: FromResponseHeadersOnly = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package cache
+
+import "strings"
+
+// Normalize parses a response Cache-Control value and serializes it back in
+// canonical form, so that equivalent headers compare equal byte for byte:
+//
+//   - directives are written in the order of String, in lower case;
+//   - a repeated directive collapses to its last occurrence, as when parsing;
+//   - delta-seconds lose their leading zeros and quotes;
+//   - field lists are canonicalized, deduplicated and sorted;
+//   - extension names are lower-cased, a repeated extension keeps its last
+//     value, extension values are only quoted when they are not tokens, and
+//     extensions are sorted.
+//
+// For example `Max-Age=060, public, max-age=60` normalizes to
+// `public, max-age=60`. Values that do not parse are returned with the error.
+func Normalize(value string) (string, error) {
+	directive, err := NewResponseCacheDirective(value)
+	if err != nil {
+		return "", err
+	}
+	directive.Extensions = normalizeExtensions(directive.Extensions)
+	return directive.StringCanonical(), nil
+}
+
+// normalizeExtensions keeps the last extension of each name and rewrites its
+// value with formatExtension.
+func normalizeExtensions(exts []string) []string {
+	var normalized []string
+	seen := make(map[string]bool, len(exts))
+	for i := len(exts) - 1; i >= 0; i-- {
+		name := extensionName(exts[i])
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		if !strings.Contains(exts[i], "=") {
+			normalized = append(normalized, name)
+			continue
+		}
+		val, _ := extensionValue(exts[i:i+1], name)
+		normalized = append(normalized, formatExtension(name, val))
+	}
+	return normalized
+}
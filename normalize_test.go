@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Max-Age=060, public, max-age=60", "public, max-age=60"},
+		{`max-age="60"`, "max-age=60"},
+		{`no-cache="x-id, set-cookie, X-Id"`, `no-cache="Set-Cookie, X-Id"`},
+		{"Zeta=1, ALPHA, zeta=2", "alpha, zeta=2"},
+		{`ext="token"`, "ext=token"},
+		{`ext="a b"`, `ext="a b"`},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got, err := Normalize(tt.value)
+		if err != nil {
+			t.Errorf("Normalize(%q): %v", tt.value, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+		if again, _ := Normalize(got); again != got {
+			t.Errorf("Normalize(%q) = %q, not idempotent", got, again)
+		}
+	}
+
+	if _, err := Normalize("max-age=abc"); !errors.Is(err, ErrMaxAgeDeltaSeconds) {
+		t.Errorf("Normalize(%q) = %v, want ErrMaxAgeDeltaSeconds", "max-age=abc", err)
+	}
+}
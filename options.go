@@ -0,0 +1,285 @@
+package cache
+
+import "context"
+
+// ParseOptions tweaks how a Cache-Control value is parsed. A nil *ParseOptions,
+// like the zero value, parses exactly like NewRequestCacheDirective and
+// NewResponseCacheDirective. Every option that makes the parser more forgiving
+// than RFC 9111 is off by default.
+type ParseOptions struct {
+	// Strict rejects any value that does not match the RFC 9110 grammar
+	// exactly, see ParseStrict. Unquoted no-cache and private values then end
+	// at the first ',' like every other value. A field name listed twice in
+	// the same no-cache or private list is reported as a Warning.
+	Strict bool
+
+	// AllowSMaxAgeMisspelling accepts `s-max-age` as an alias of the response
+	// `s-maxage` directive and reports a Warning when it does. Without it the
+	// misspelling ends up in Extensions and shared caches silently fall back
+	// to max-age.
+	AllowSMaxAgeMisspelling bool
+
+	// PreserveExtensionCase records cache-extension names in Extensions as
+	// written instead of lower-cased. Directive names are always matched
+	// case-insensitively and no-cache/private field names are always
+	// canonicalized with http.CanonicalHeaderKey, regardless of this option.
+	PreserveExtensionCase bool
+
+	// DecodeExtensionValues percent-decodes cache-extension values, after
+	// unquoting, before storing them in Extensions. Values that are no longer
+	// a token once decoded are stored quoted. Invalid percent-encoding, and
+	// encoding of a control character other than HTAB, fails with
+	// ErrExtensionValueEncoding. Values are kept raw by default.
+	DecodeExtensionValues bool
+
+	// SplitFieldListOnWhitespace also splits no-cache and private field lists
+	// on spaces and tabs, for origins that write
+	// `no-cache="Set-Cookie Authorization"`. Strictly that is a single,
+	// malformed field name; with this option it lists two fields and a
+	// Warning is reported. Field lists are split on ',' only by default.
+	SplitFieldListOnWhitespace bool
+
+	// AllowSingleQuotes accepts values quoted with single quotes, as some
+	// broken clients send `no-cache='Set-Cookie'`, and reports a Warning when
+	// it does. By default, and always with Strict, a single quote is an
+	// ordinary token character (RFC 9110 Section 5.6.2).
+	AllowSingleQuotes bool
+
+	// TruncateDeltaSeconds parses the leading digits of a delta-seconds value
+	// followed by other characters, as in `max-age=60abc` or `max-age=60;`,
+	// and reports a Warning when it does. Such values are rejected by default.
+	TruncateDeltaSeconds bool
+
+	// OnInvalidQuotedByte selects what happens to a control character other
+	// than HTAB inside a quoted-string, where RFC 9110 does not allow it. It
+	// is replaced with '?' by default.
+	OnInvalidQuotedByte InvalidQuotedByteMode
+
+	// StripBrackets removes a single pair of brackets or braces around the
+	// whole value, as written by broken middleware that sends
+	// `[max-age=60, public]`, and reports a Warning when it does. It applies
+	// before the Strict grammar check. Brackets are not special by default.
+	StripBrackets bool
+
+	// StripTrailingComment removes a parenthesized comment at the end of the
+	// value, as appended by legacy tooling in `max-age=60 (cdn)`, stores its
+	// text in the Comment field and reports a Warning. It applies before the
+	// Strict grammar check. Parentheses are not allowed outside quoted-strings,
+	// so without this option such values are rejected by Strict and produce
+	// meaningless extensions otherwise.
+	StripTrailingComment bool
+
+	// AllowExtendedWhitespace treats vertical tabs and form feeds around
+	// directives like spaces, and reports a Warning when it meets one. CR and
+	// LF are always rejected, so that a header can never be split. By default
+	// RFC 9110 OWS (SP and HTAB) is the only whitespace and other control
+	// characters outside quoted strings are a SyntaxError.
+	AllowExtendedWhitespace bool
+
+	// ExtensionsCapacityHint pre-sizes the Extensions slice for headers known
+	// to carry many cache-extensions, avoiding repeated reallocation.
+	ExtensionsCapacityHint int
+
+	// OnWarning, if set, is called for every non-fatal problem found while
+	// parsing.
+	OnWarning func(Warning)
+
+	// OnDirective, if set, is called for every directive once it has been
+	// recorded, in header order, to trace how a value was tokenized. name is
+	// the lower-cased directive name after alias resolution, as written for
+	// cache-extensions with PreserveExtensionCase, and value its unquoted
+	// value, empty for a bare directive. Directives that fail to parse are not
+	// reported.
+	OnDirective func(name, value string, kind DirectiveKind)
+}
+
+// DirectiveKind tells how a directive was recorded, see
+// ParseOptions.OnDirective.
+type DirectiveKind int
+
+const (
+	// StandardDirective is a directive the parsed type has a field for.
+	StandardDirective DirectiveKind = iota
+
+	// ExtensionDirective is a directive recorded in Extensions.
+	ExtensionDirective
+)
+
+func (k DirectiveKind) String() string {
+	switch k {
+	case StandardDirective:
+		return "standard"
+	case ExtensionDirective:
+		return "extension"
+	}
+	return "unknown"
+}
+
+// InvalidQuotedByteMode is the handling of invalid bytes in quoted-strings,
+// see ParseOptions.OnInvalidQuotedByte.
+type InvalidQuotedByteMode int
+
+const (
+	// SubstituteInvalidQuotedByte replaces each invalid byte with '?'.
+	SubstituteInvalidQuotedByte InvalidQuotedByteMode = iota
+
+	// ErrorOnInvalidQuotedByte fails parsing with a SyntaxError pointing at
+	// the invalid byte.
+	ErrorOnInvalidQuotedByte
+
+	// StripInvalidQuotedByte drops invalid bytes from the value.
+	StripInvalidQuotedByte
+)
+
+// Warning describes a non-fatal problem found while parsing.
+type Warning struct {
+	// Directive is the lower-cased directive name as written in the header,
+	// or empty for a problem with the value as a whole.
+	Directive string
+
+	// Message explains the problem.
+	Message string
+}
+
+func (w Warning) String() string {
+	if w.Directive == "" {
+		return w.Message
+	}
+	return w.Directive + ": " + w.Message
+}
+
+// ParseRequest parses a request Cache-Control value using opts.
+func ParseRequest(value string, opts *ParseOptions) (*RequestCacheDirective, error) {
+	directive := newRequestCacheDirective()
+	directive.Extensions = opts.extensions()
+	if err := parseCacheControlv(context.Background(), directive, value, opts); err != nil {
+		return nil, err
+	}
+	if len(directive.Extensions) == 0 {
+		directive.Extensions = nil
+	}
+	return directive, nil
+}
+
+// ParseResponse parses a response Cache-Control value using opts.
+func ParseResponse(value string, opts *ParseOptions) (*ResponseCacheDirective, error) {
+	return parseResponse(context.Background(), value, opts)
+}
+
+// ParseResponseContext parses a response Cache-Control value, giving up with
+// ctx.Err() once ctx is done. Parsing is normally far too fast to need this,
+// but it bounds the work spent on pathological multi-megabyte values.
+//
+// ctx is checked before parsing starts and then once every 64 directives, so
+// a single huge directive is never interrupted halfway.
+func ParseResponseContext(ctx context.Context, value string) (*ResponseCacheDirective, error) {
+	return parseResponse(ctx, value, nil)
+}
+
+func parseResponse(ctx context.Context, value string, opts *ParseOptions) (*ResponseCacheDirective, error) {
+	directive := newResponseCacheDirective()
+	directive.Extensions = opts.extensions()
+	if err := parseCacheControlv(ctx, directive, value, opts); err != nil {
+		return nil, err
+	}
+	if len(directive.Extensions) == 0 {
+		directive.Extensions = nil
+	}
+	return directive, nil
+}
+
+// extensions returns the initial Extensions slice, sized by
+// ExtensionsCapacityHint.
+func (opts *ParseOptions) extensions() []string {
+	if opts == nil || opts.ExtensionsCapacityHint <= 0 {
+		return nil
+	}
+	return make([]string, 0, opts.ExtensionsCapacityHint)
+}
+
+func (opts *ParseOptions) strict() bool {
+	return opts != nil && opts.Strict
+}
+
+func (opts *ParseOptions) singleQuotes() bool {
+	return opts != nil && opts.AllowSingleQuotes && !opts.Strict
+}
+
+func (opts *ParseOptions) invalidQuotedByte() InvalidQuotedByteMode {
+	if opts == nil {
+		return SubstituteInvalidQuotedByte
+	}
+	return opts.OnInvalidQuotedByte
+}
+
+func (opts *ParseOptions) directive(name, value string, kind DirectiveKind) {
+	if opts != nil && opts.OnDirective != nil {
+		opts.OnDirective(name, value, kind)
+	}
+}
+
+func (opts *ParseOptions) warn(directive, message string) {
+	if opts != nil && opts.OnWarning != nil {
+		opts.OnWarning(Warning{Directive: directive, Message: message})
+	}
+}
+
+// resolveName maps the lower-cased name of a directive to the one the
+// directive types understand, applying registered aliases first.
+func (opts *ParseOptions) resolveName(name string) string {
+	name = resolveAlias(name)
+	if opts == nil {
+		return name
+	}
+	if opts.AllowSMaxAgeMisspelling && name == "s-max-age" {
+		opts.warn(name, "misspelling of `s-maxage`, treated as `s-maxage`")
+		return HeaderSMaxAge
+	}
+	return name
+}
+
+// Parsed is everything ParseRequestDetailed learned about a request
+// Cache-Control value.
+type Parsed struct {
+	// Request is the parsed directive, or nil when the value could not be
+	// parsed.
+	Request *RequestCacheDirective
+
+	// Warnings lists the non-fatal problems met while parsing, in order.
+	Warnings []Warning
+
+	// Errors holds the parse error when Request is nil. Otherwise it holds the
+	// semantic problems reported by Validate, which do not prevent using
+	// Request.
+	Errors []error
+}
+
+// ParseRequestDetailed parses a request Cache-Control value like ParseRequest,
+// collecting warnings and errors into a single result for linters and
+// gateways. OnWarning in opts, if set, is still called for every warning.
+// Parsing stops at the first syntax error, so at most one parse error is
+// reported.
+func ParseRequestDetailed(value string, opts *ParseOptions) Parsed {
+	var parsed Parsed
+
+	collect := ParseOptions{}
+	if opts != nil {
+		collect = *opts
+	}
+	onWarning := collect.OnWarning
+	collect.OnWarning = func(w Warning) {
+		parsed.Warnings = append(parsed.Warnings, w)
+		if onWarning != nil {
+			onWarning(w)
+		}
+	}
+
+	directive, err := ParseRequest(value, &collect)
+	if err != nil {
+		parsed.Errors = []error{err}
+		return parsed
+	}
+	parsed.Request = directive
+	parsed.Errors = directive.Validate()
+	return parsed
+}
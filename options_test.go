@@ -0,0 +1,554 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// manyExtensions returns a response value with n cache-extensions.
+func manyExtensions(n int) string {
+	members := make([]string, 0, n+1)
+	members = append(members, "max-age=60")
+	for i := 0; i < n; i++ {
+		members = append(members, "ext"+strconv.Itoa(i)+"=value")
+	}
+	return strings.Join(members, ", ")
+}
+
+func TestExtensionsCapacityHint(t *testing.T) {
+	value := manyExtensions(50)
+	directive, err := ParseResponse(value, &ParseOptions{ExtensionsCapacityHint: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(directive.Extensions) != 50 || cap(directive.Extensions) != 50 {
+		t.Errorf("len, cap = %d, %d, want 50, 50", len(directive.Extensions), cap(directive.Extensions))
+	}
+
+	directive, err = ParseResponse("max-age=60", &ParseOptions{ExtensionsCapacityHint: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if directive.Extensions != nil {
+		t.Errorf("Extensions = %q, want nil", directive.Extensions)
+	}
+}
+
+func BenchmarkParseResponseExtensions(b *testing.B) {
+	value := manyExtensions(50)
+	for _, bb := range []struct {
+		name string
+		opts *ParseOptions
+	}{
+		{"NoHint", nil},
+		{"CapacityHint", &ParseOptions{ExtensionsCapacityHint: 50}},
+	} {
+		b.Run(bb.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := ParseResponse(value, bb.opts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeExtensionValues(t *testing.T) {
+	tests := []struct {
+		value string
+		ext   string
+		err   bool
+	}{
+		{"ext=a%2Fb", `ext="a/b"`, false},
+		{"ext=a%2Db", "ext=a-b", false},
+		{"ext=%41%42", "ext=AB", false},
+		{`ext="a%20b"`, `ext="a b"`, false},
+		{"ext=plain", "ext=plain", false},
+		{"ext=%", "", true},
+		{"ext=%4", "", true},
+		{"ext=%zz", "", true},
+		{"ext=a%09b", "ext=\"a\tb\"", false},
+		{"0=%00", "", true},
+		{"ext=a%01b", "", true},
+		{"ext=%0D%0A", "", true},
+		{`ext="a%7Fb"`, "", true},
+	}
+	for _, tt := range tests {
+		directive, err := ParseResponse(tt.value, &ParseOptions{DecodeExtensionValues: true})
+		if tt.err {
+			if !errors.Is(err, ErrExtensionValueEncoding) {
+				t.Errorf("ParseResponse(%q) = %v, want ErrExtensionValueEncoding", tt.value, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseResponse(%q): %v", tt.value, err)
+			continue
+		}
+		if len(directive.Extensions) != 1 || directive.Extensions[0] != tt.ext {
+			t.Errorf("ParseResponse(%q).Extensions = %q, want [%q]", tt.value, directive.Extensions, tt.ext)
+		}
+	}
+
+	directive, err := ParseResponse("ext=a%2Fb, bad=%zz", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := directive.String(), "ext=a%2Fb, bad=%zz"; got != want {
+		t.Errorf("values are decoded by default: String() = %q, want %q", got, want)
+	}
+}
+
+func TestCaseHandlingMatrix(t *testing.T) {
+	const value = `Max-Age=60, NO-CACHE="set-cookie", Private=X-REQUEST-ID, X-Vendor=Value, FLAG`
+	tests := []struct {
+		name       string
+		opts       *ParseOptions
+		extensions []string
+	}{
+		{"default", nil, []string{"x-vendor=Value", "flag"}},
+		{"preserve extension case", &ParseOptions{PreserveExtensionCase: true}, []string{"X-Vendor=Value", "FLAG"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			directive, err := ParseResponse(value, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if directive.MaxAge != 60 {
+				t.Errorf("MaxAge = %d, want 60", directive.MaxAge)
+			}
+			if !directive.NoCache["Set-Cookie"] || !directive.Private["X-Request-Id"] {
+				t.Errorf("NoCache, Private = %v, %v, want canonicalized field names", directive.NoCache, directive.Private)
+			}
+			if len(directive.Extensions) != len(tt.extensions) {
+				t.Fatalf("Extensions = %q, want %q", directive.Extensions, tt.extensions)
+			}
+			for i, ext := range tt.extensions {
+				if directive.Extensions[i] != ext {
+					t.Errorf("Extensions = %q, want %q", directive.Extensions, tt.extensions)
+				}
+			}
+			if value, ok := directive.ExtensionValue("x-VENDOR"); !ok || value != "Value" {
+				t.Errorf("ExtensionValue(x-VENDOR) = %q, %v, want Value, true", value, ok)
+			}
+		})
+	}
+}
+
+func TestSplitFieldListOnWhitespace(t *testing.T) {
+	tests := []struct {
+		value   string
+		split   []string
+		unsplit []string
+	}{
+		{`no-cache="Set-Cookie, Authorization"`, []string{"Authorization", "Set-Cookie"}, []string{"Authorization", "Set-Cookie"}},
+		{`no-cache="Set-Cookie Authorization"`, []string{"Authorization", "Set-Cookie"}, []string{"Set-Cookie Authorization"}},
+		{"no-cache=\"Set-Cookie\tAuthorization\"", []string{"Authorization", "Set-Cookie"}, []string{"Set-Cookie\tAuthorization"}},
+		{`no-cache="Set-Cookie Authorization, X-Id"`, []string{"Authorization", "Set-Cookie", "X-Id"}, []string{"Set-Cookie Authorization", "X-Id"}},
+	}
+	for _, tt := range tests {
+		for _, mode := range []struct {
+			name string
+			opts *ParseOptions
+			want []string
+		}{
+			{"default", nil, tt.unsplit},
+			{"split", &ParseOptions{SplitFieldListOnWhitespace: true}, tt.split},
+		} {
+			directive, err := ParseResponse(tt.value, mode.opts)
+			if err != nil {
+				t.Errorf("%s: ParseResponse(%q): %v", mode.name, tt.value, err)
+				continue
+			}
+			if got := sortedFields(directive.NoCache); strings.Join(got, "|") != strings.Join(mode.want, "|") {
+				t.Errorf("%s: ParseResponse(%q).NoCache = %q, want %q", mode.name, tt.value, got, mode.want)
+			}
+		}
+	}
+
+	var warnings []Warning
+	opts := &ParseOptions{SplitFieldListOnWhitespace: true, OnWarning: func(w Warning) { warnings = append(warnings, w) }}
+	if _, err := ParseResponse(`private="Set-Cookie Authorization"`, opts); err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 1 || warnings[0].Directive != HeaderPrivate {
+		t.Errorf("warnings = %v, want one for private", warnings)
+	}
+
+	if _, err := ParseResponse(`no-cache="Set-Cookie Authorization"`, &ParseOptions{Strict: true}); !errors.Is(err, ErrInvalidFieldName) {
+		t.Errorf("strict: ParseResponse = %v, want ErrInvalidFieldName", err)
+	}
+}
+
+func TestAllowSingleQuotes(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    *ParseOptions
+		noCache []string
+		ext     string
+	}{
+		{"default", nil, []string{"'set-Cookie'"}, "ext='abc'"},
+		{"strict", &ParseOptions{Strict: true, AllowSingleQuotes: true}, []string{"'set-Cookie'"}, "ext='abc'"},
+		{"lenient", &ParseOptions{AllowSingleQuotes: true}, []string{"Set-Cookie"}, `ext="abc"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A single quote is a tchar, so strictly the quotes are part of
+			// the field name.
+			directive, err := ParseResponse("no-cache='Set-Cookie'", tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sortedFields(directive.NoCache); strings.Join(got, "|") != strings.Join(tt.noCache, "|") {
+				t.Errorf("NoCache = %q, want %q", got, tt.noCache)
+			}
+
+			directive, err = ParseResponse("ext='abc'", tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(directive.Extensions) != 1 || directive.Extensions[0] != tt.ext {
+				t.Errorf("Extensions = %q, want [%q]", directive.Extensions, tt.ext)
+			}
+		})
+	}
+}
+
+func TestTruncateDeltaSeconds(t *testing.T) {
+	tests := []struct {
+		value   string
+		garbage string
+	}{
+		{"max-age=60abc", "abc"},
+		{"max-age=60 ", ""},
+		{"max-age=60;", ";"},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.value)
+		if tt.garbage == "" {
+			if err != nil || directive.MaxAge != 60 {
+				t.Errorf("NewResponseCacheDirective(%q) = %v, %v, want max-age=60", tt.value, directive, err)
+			}
+		} else if !errors.Is(err, ErrMaxAgeDeltaSeconds) || !errors.Is(err, ErrDeltaSecondsSyntax) || !strings.Contains(err.Error(), strconv.Quote(tt.garbage)) {
+			t.Errorf("NewResponseCacheDirective(%q) = %v, want ErrDeltaSecondsSyntax naming %q", tt.value, err, tt.garbage)
+		}
+
+		var warnings []Warning
+		opts := &ParseOptions{TruncateDeltaSeconds: true, OnWarning: func(w Warning) { warnings = append(warnings, w) }}
+		directive, err = ParseResponse(tt.value, opts)
+		if err != nil || directive.MaxAge != 60 {
+			t.Errorf("lenient: ParseResponse(%q) = %v, %v, want max-age=60", tt.value, directive, err)
+		}
+		if want := len(tt.garbage) > 0; (len(warnings) == 1) != want || len(warnings) > 1 {
+			t.Errorf("lenient: ParseResponse(%q) warnings = %v, want one: %v", tt.value, warnings, want)
+		}
+	}
+}
+
+func TestOnInvalidQuotedByte(t *testing.T) {
+	const value = "no-cache=\"Set-Cookie\x7f\", ext=\"a\x01b\""
+	tests := []struct {
+		mode    InvalidQuotedByteMode
+		noCache string
+		ext     string
+	}{
+		{SubstituteInvalidQuotedByte, "Set-Cookie?", `ext="a?b"`},
+		{StripInvalidQuotedByte, "Set-Cookie", `ext="ab"`},
+		{ErrorOnInvalidQuotedByte, "", ""},
+	}
+	for _, tt := range tests {
+		directive, err := ParseResponse(value, &ParseOptions{OnInvalidQuotedByte: tt.mode})
+		if tt.mode == ErrorOnInvalidQuotedByte {
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) || syntaxErr.Offset != 20 {
+				t.Errorf("mode %d: ParseResponse(%q) = %v, want a SyntaxError at offset 20", tt.mode, value, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("mode %d: ParseResponse(%q): %v", tt.mode, value, err)
+			continue
+		}
+		if len(directive.NoCache) != 1 || !directive.NoCache[tt.noCache] {
+			t.Errorf("mode %d: NoCache = %q, want %q", tt.mode, sortedFields(directive.NoCache), tt.noCache)
+		}
+		if len(directive.Extensions) != 1 || directive.Extensions[0] != tt.ext {
+			t.Errorf("mode %d: Extensions = %q, want [%q]", tt.mode, directive.Extensions, tt.ext)
+		}
+	}
+}
+
+func TestOnInvalidQuotedByteEscaped(t *testing.T) {
+	tests := []struct {
+		value  string
+		mode   InvalidQuotedByteMode
+		ext    string
+		offset int
+	}{
+		// A backslash followed by a letter is just that letter.
+		{`ext="a\rb"`, SubstituteInvalidQuotedByte, "arb", -1},
+		{`ext="a\nb"`, StripInvalidQuotedByte, "anb", -1},
+		{`ext="a\0b"`, ErrorOnInvalidQuotedByte, "a0b", -1},
+
+		// A backslash followed by a control byte is as invalid as the byte alone.
+		{"ext=\"a\\\rb\"", SubstituteInvalidQuotedByte, "a?b", -1},
+		{"ext=\"a\\\nb\"", SubstituteInvalidQuotedByte, "a?b", -1},
+		{"ext=\"a\\\x00b\"", SubstituteInvalidQuotedByte, "a?b", -1},
+		{"ext=\"a\\\rb\"", StripInvalidQuotedByte, "ab", -1},
+		{"ext=\"a\\\nb\"", StripInvalidQuotedByte, "ab", -1},
+		{"ext=\"a\\\x00b\"", StripInvalidQuotedByte, "ab", -1},
+		{"ext=\"a\\\rb\"", ErrorOnInvalidQuotedByte, "", 7},
+		{"ext=\"a\\\nb\"", ErrorOnInvalidQuotedByte, "", 7},
+		{"ext=\"a\\\x00b\"", ErrorOnInvalidQuotedByte, "", 7},
+	}
+	for _, tt := range tests {
+		directive, err := ParseResponse(tt.value, &ParseOptions{OnInvalidQuotedByte: tt.mode})
+		if tt.offset >= 0 {
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) || syntaxErr.Offset != tt.offset {
+				t.Errorf("mode %d: ParseResponse(%q) = %v, want a SyntaxError at offset %d", tt.mode, tt.value, err, tt.offset)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("mode %d: ParseResponse(%q): %v", tt.mode, tt.value, err)
+			continue
+		}
+		if value, _ := directive.ExtensionValue("ext"); value != tt.ext {
+			t.Errorf("mode %d: ParseResponse(%q) ext = %q, want %q", tt.mode, tt.value, value, tt.ext)
+		}
+	}
+}
+
+func TestStripBrackets(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"[max-age=60, public]", "public, max-age=60"},
+		{"{max-age=60, public}", "public, max-age=60"},
+		{" [max-age=60] ", "max-age=60"},
+		{"[]", ""},
+	}
+	for _, tt := range tests {
+		var syntaxErr *SyntaxError
+		if _, err := ParseResponse(tt.value, &ParseOptions{Strict: true}); !errors.As(err, &syntaxErr) {
+			t.Errorf("strict: ParseResponse(%q) = %v, want a SyntaxError", tt.value, err)
+		}
+
+		for _, opts := range []*ParseOptions{{StripBrackets: true}, {StripBrackets: true, Strict: true}} {
+			var warnings []Warning
+			opts.OnWarning = func(w Warning) { warnings = append(warnings, w) }
+			directive, err := ParseResponse(tt.value, opts)
+			if err != nil {
+				t.Errorf("strip, strict %v: ParseResponse(%q): %v", opts.Strict, tt.value, err)
+				continue
+			}
+			if got := directive.String(); got != tt.want || len(directive.Extensions) != 0 {
+				t.Errorf("strip, strict %v: ParseResponse(%q) = %q, want %q", opts.Strict, tt.value, got, tt.want)
+			}
+			if len(warnings) != 1 {
+				t.Errorf("strip, strict %v: ParseResponse(%q) warnings = %v, want one", opts.Strict, tt.value, warnings)
+			}
+		}
+	}
+
+	// Only a matching pair around the whole value is stripped.
+	for _, value := range []string{"[max-age=60", "[max-age=60}"} {
+		if _, err := ParseResponse(value, &ParseOptions{StripBrackets: true, Strict: true}); err == nil {
+			t.Errorf("strip, strict: ParseResponse(%q) succeeded, want an error", value)
+		}
+	}
+}
+
+func TestStripTrailingComment(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		comment string
+	}{
+		{"max-age=60 (cdn)", "max-age=60", "cdn"},
+		{"max-age=60, (generated by X (v2))", "max-age=60", "generated by X (v2)"},
+		{"(cdn)", "", "cdn"},
+	}
+	for _, tt := range tests {
+		var syntaxErr *SyntaxError
+		if _, err := ParseResponse(tt.value, &ParseOptions{Strict: true}); !errors.As(err, &syntaxErr) {
+			t.Errorf("strict: ParseResponse(%q) = %v, want a SyntaxError", tt.value, err)
+		}
+
+		var warnings []Warning
+		opts := &ParseOptions{StripTrailingComment: true, OnWarning: func(w Warning) { warnings = append(warnings, w) }}
+		directive, err := ParseResponse(tt.value, opts)
+		if err != nil {
+			t.Errorf("lenient: ParseResponse(%q): %v", tt.value, err)
+			continue
+		}
+		if got := directive.String(); got != tt.want || directive.Comment != tt.comment {
+			t.Errorf("lenient: ParseResponse(%q) = %q with Comment %q, want %q with Comment %q", tt.value, got, directive.Comment, tt.want, tt.comment)
+		}
+		if len(warnings) != 1 {
+			t.Errorf("lenient: ParseResponse(%q) warnings = %v, want one", tt.value, warnings)
+		}
+	}
+
+	directive, err := ParseResponse("max-age=60 (cdn)", &ParseOptions{StripTrailingComment: true, Strict: true})
+	if err != nil || directive.MaxAge != 60 || directive.Comment != "cdn" {
+		t.Errorf("strict and lenient: ParseResponse = %v, %v, want max-age=60 with Comment cdn", directive, err)
+	}
+
+	// Only a comment at the very end is stripped.
+	directive, err = ParseResponse("max-age=60 (cdn), public", &ParseOptions{StripTrailingComment: true})
+	if err != nil || directive.Comment != "" {
+		t.Errorf("ParseResponse with a comment in the middle = %v, %v, want no Comment", directive, err)
+	}
+}
+
+func TestOnDirective(t *testing.T) {
+	type call struct {
+		name, value string
+		kind        DirectiveKind
+	}
+	var calls []call
+	opts := &ParseOptions{
+		AllowSMaxAgeMisspelling: true,
+		OnDirective: func(name, value string, kind DirectiveKind) {
+			calls = append(calls, call{name, value, kind})
+		},
+	}
+	_, err := ParseResponse(`Public, max-age="60", no-cache="Set-Cookie", s-max-age=10, X-Ext=a, flag`, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []call{
+		{"public", "", StandardDirective},
+		{"max-age", "60", StandardDirective},
+		{"no-cache", "Set-Cookie", StandardDirective},
+		{"s-maxage", "10", StandardDirective},
+		{"x-ext", "a", ExtensionDirective},
+		{"flag", "", ExtensionDirective},
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("call %d = %v, want %v", i, calls[i], want[i])
+		}
+	}
+
+	// A directive that fails to parse is not reported.
+	calls = nil
+	if _, err := ParseResponse("public, max-age=abc", opts); err == nil {
+		t.Fatal("ParseResponse(max-age=abc) succeeded")
+	}
+	if len(calls) != 1 || calls[0].name != "public" {
+		t.Errorf("calls = %v, want only public", calls)
+	}
+}
+
+func TestParseRequestDetailed(t *testing.T) {
+	tests := []struct {
+		value    string
+		opts     *ParseOptions
+		request  string
+		warnings int
+		errs     []error
+	}{
+		{"max-age=60", nil, "max-age=60", 0, nil},
+		{"max-age=60abc", &ParseOptions{TruncateDeltaSeconds: true}, "max-age=60", 1, nil},
+		{"[max-age=60, max-stale=10abc]", &ParseOptions{StripBrackets: true, TruncateDeltaSeconds: true}, "max-age=60, max-stale=10", 2, nil},
+		{"only-if-cached, no-cache", nil, "no-cache, only-if-cached", 0, []error{ErrOnlyIfCachedWithNoCache}},
+		{"max-stale, min-fresh=10, only-if-cached, no-store", nil, "max-stale, min-fresh=10, no-store, only-if-cached", 0,
+			[]error{ErrOnlyIfCachedWithNoStore, ErrMaxStaleWithMinFresh}},
+		{"max-age=60abc", nil, "", 0, []error{ErrMaxAgeDeltaSeconds}},
+		{`max-age=60, ext="unterminated`, nil, "", 0, []error{ErrMissingClosingQuote}},
+	}
+	for _, tt := range tests {
+		parsed := ParseRequestDetailed(tt.value, tt.opts)
+		if tt.request == "" {
+			if parsed.Request != nil {
+				t.Errorf("ParseRequestDetailed(%q).Request = %q, want nil", tt.value, parsed.Request)
+			}
+		} else if parsed.Request == nil || parsed.Request.String() != tt.request {
+			t.Errorf("ParseRequestDetailed(%q).Request = %v, want %q", tt.value, parsed.Request, tt.request)
+		}
+		if len(parsed.Warnings) != tt.warnings {
+			t.Errorf("ParseRequestDetailed(%q).Warnings = %v, want %d", tt.value, parsed.Warnings, tt.warnings)
+		}
+		if len(parsed.Errors) != len(tt.errs) {
+			t.Errorf("ParseRequestDetailed(%q).Errors = %v, want %v", tt.value, parsed.Errors, tt.errs)
+			continue
+		}
+		for i, err := range tt.errs {
+			if !errors.Is(parsed.Errors[i], err) {
+				t.Errorf("ParseRequestDetailed(%q).Errors[%d] = %v, want %v", tt.value, i, parsed.Errors[i], err)
+			}
+		}
+	}
+
+	// The caller's OnWarning still sees every warning.
+	var warnings []Warning
+	opts := &ParseOptions{TruncateDeltaSeconds: true, OnWarning: func(w Warning) { warnings = append(warnings, w) }}
+	parsed := ParseRequestDetailed("max-age=60abc, min-fresh=5s", opts)
+	if len(warnings) != 2 || len(parsed.Warnings) != 2 || warnings[1] != parsed.Warnings[1] {
+		t.Errorf("OnWarning saw %v, Warnings = %v, want the same two", warnings, parsed.Warnings)
+	}
+}
+
+// cancelAfterContext reports itself canceled once Err has been called more
+// than after times, to cancel a parse halfway.
+type cancelAfterContext struct {
+	context.Context
+	after, checks int
+}
+
+func (ctx *cancelAfterContext) Err() error {
+	ctx.checks++
+	if ctx.checks > ctx.after {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestParseResponseContext(t *testing.T) {
+	value := manyExtensions(3 * contextCheckInterval)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if directive, err := ParseResponseContext(ctx, "max-age=60"); !errors.Is(err, context.Canceled) || directive != nil {
+		t.Errorf("ParseResponseContext(canceled) = %v, %v, want context.Canceled", directive, err)
+	}
+
+	ctx, cancel = context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	if _, err := ParseResponseContext(ctx, value); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ParseResponseContext(expired) = %v, want context.DeadlineExceeded", err)
+	}
+
+	// Canceled after the check before parsing: the first periodic check stops it.
+	halfway := &cancelAfterContext{Context: context.Background(), after: 1}
+	if _, err := ParseResponseContext(halfway, value); !errors.Is(err, context.Canceled) || halfway.checks != 2 {
+		t.Errorf("ParseResponseContext(canceled halfway) = %v after %d checks, want context.Canceled after 2", err, halfway.checks)
+	}
+
+	// A value shorter than the check interval is only checked once.
+	short := &cancelAfterContext{Context: context.Background(), after: 1}
+	if directive, err := ParseResponseContext(short, manyExtensions(contextCheckInterval-2)); err != nil || directive == nil || !directive.MaxAgePresent {
+		t.Errorf("ParseResponseContext(short value) = %v, %v, want max-age=60", directive, err)
+	}
+
+	directive, err := ParseResponseContext(context.Background(), value)
+	if err != nil {
+		t.Fatalf("ParseResponseContext(live): %v", err)
+	}
+	if len(directive.Extensions) != 3*contextCheckInterval {
+		t.Errorf("ParseResponseContext(live) has %d extensions, want %d", len(directive.Extensions), 3*contextCheckInterval)
+	}
+}
@@ -1,103 +1,163 @@
 package cache
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 )
 
 var (
-	ErrMissingClosingQuote = errors.New("missing closing quote")
+	ErrMissingClosingQuote    = errors.New("missing closing quote")
+	ErrEmptyDirectiveName     = errors.New("empty directive name before '='")
+	ErrExtensionValueEncoding = errors.New("invalid percent-encoding in cache-extension value")
 )
 
+// contextCheckInterval is the number of directives parsed between two checks
+// of the context passed to parseCacheControlv.
+const contextCheckInterval = 64
+
 // parseCacheControlv is a function that parses a Cache-Control header directive value
 // and sets the corresponding directive in the given directive object.
 //
-// The function iterates through the string value of the directive, skipping any leading whitespace or commas.
-// It then finds the end of the current token (a sequence of characters that conform to the token ABNF definition),
-// converts the token to lowercase, and determines whether an extension field is required for the token.
-//
-// If the current character is an equals sign, indicating that the token is a key-value pair,
-// the function checks whether the value is enclosed in quotes.
-// If it is, it uses parseQuotedString to extract the value, updates the index to skip over the value,
-// and sets the directive pair with the token and value.
-// If the value is not enclosed in quotes, the function finds the end of the value and sets the directive pair with the token and value.
+// The value is split into directives by a DirectiveScanner, see Scan for the lexical
+// rules. Each directive name is lower-cased and resolved through opts, see ParseOptions,
+// then handed to the directive with setToken or setPair depending on whether it
+// carries a value.
 //
-// If the current character is not an equals sign, indicating that the token is a single value,
-// the function sets the directive token with the current token.
+// ctx is checked before parsing and then every contextCheckInterval directives.
 //
 // The function returns an error if an unexpected character is encountered or if a quoted string is not closed.
-func parseCacheControlv(d directive, val string) error {
-	var (
-		index = 0
-		vl    = len(val)
-	)
-
-	for index < vl {
-		if isWhiteSpace(val[index]) || val[index] == ',' {
-			index++
-			continue
+func parseCacheControlv(ctx context.Context, d directive, val string, opts *ParseOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if opts != nil && opts.StripBrackets {
+		if inner, ok := stripBrackets(val); ok {
+			opts.warn("", "value wrapped in brackets or braces")
+			val = inner
+		}
+	}
+	if opts != nil && opts.StripTrailingComment {
+		if rest, comment, ok := stripTrailingComment(val); ok {
+			opts.warn("", "trailing comment ("+comment+")")
+			d.setComment(comment)
+			val = rest
 		}
+	}
+	if opts.strict() {
+		if err := validateGrammar(val); err != nil {
+			return err
+		}
+	}
 
-		// Find the end of the token
-		tokenEnd := index + 1
-		for tokenEnd < vl {
-			if !isToken(val[tokenEnd]) {
-				break
-			}
-			tokenEnd++
-		}
-
-		// Get the lowercase token string and check if it requires an extension field
-		token := strings.ToLower(val[index:tokenEnd])
-		requireExtensionField := tokenRequireExtensionFields(token)
-
-		// If the token has an equals sign, it's a pair
-		if tokenEnd+1 < vl && val[tokenEnd] == '=' {
-			valueStart := tokenEnd + 1
-
-			// If the value is quoted, parse the quoted string
-			if valueStart < vl && val[valueStart] == '"' {
-				eaten, value := parseQuotedString(val[valueStart:])
-				if eaten == -1 {
-					return ErrMissingClosingQuote
-				}
-
-				index = valueStart + eaten
-				if err := d.setPair(token, value); err != nil {
-					return err
-				}
-			} else {
-				// If the value is not quoted, find the end of the pair value
-				valueEnd := valueStart
-				for valueEnd < vl {
-					if isWhiteSpace(val[valueEnd]) ||
-						(!requireExtensionField && val[valueEnd] == ',') {
-						break
-					}
-					valueEnd++
-				}
-				index = valueEnd
-
-				// Remove the trailing comma if there is one and update the directive with the pair
-				value := val[valueStart:valueEnd]
-				if value != "" && value[len(value)-1] == ',' {
-					value = value[:len(value)-1]
-				}
-				if err := d.setPair(token, value); err != nil {
-					return err
-				}
+	s := &DirectiveScanner{
+		val:                val,
+		strict:             opts.strict(),
+		singleQuotes:       opts.singleQuotes(),
+		invalidQuotedByte:  opts.invalidQuotedByte(),
+		extendedWhitespace: opts != nil && opts.AllowExtendedWhitespace,
+	}
+	for n := 1; s.Scan(); n++ {
+		if n%contextCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
 			}
+		}
+
+		token := opts.resolveName(s.lower)
+		name := token
+		if opts != nil && opts.PreserveExtensionCase {
+			name = s.name
+		}
+
+		if s.singleQuoted {
+			opts.warn(s.lower, "value quoted with single quotes")
+		}
+
+		var err error
+		if s.hasValue {
+			err = setDirectivePair(d, token, name, s.value, s.raw, opts)
 		} else {
-			// If the token doesn't have an equals sign, it's a simple token
-			if token != "," {
-				if err := d.setToken(token); err != nil {
-					return err
-				}
-			}
-			index = tokenEnd
+			err = setDirectiveToken(d, token, name, opts)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if s.sawExtendedWhitespace {
+		opts.warn("", "whitespace other than SP and HTAB")
+	}
+	return s.Err()
+}
+
+// setDirectiveToken hands a bare token to d, recording it as a cache-extension
+// spelled name when d does not know the directive.
+func setDirectiveToken(d directive, token, name string, opts *ParseOptions) error {
+	err := d.setToken(token)
+	switch err {
+	case nil:
+		opts.directive(token, "", StandardDirective)
+	case errCacheExtension:
+		d.addExtension(name)
+		opts.directive(name, "", ExtensionDirective)
+		return nil
+	}
+	return err
+}
+
+// setDirectivePair hands a key/value pair to d. When d does not know the directive
+// it is recorded as a cache-extension spelled name, with raw, the value as it was
+// written in the header, quotes included, so that a quoted extension value keeps
+// its grouping when serialized again.
+func setDirectivePair(d directive, key, name, val, raw string, opts *ParseOptions) error {
+	if opts.strict() && tokenRequireExtensionFields(key) {
+		if err := validateFieldList(val); err != nil {
+			return newDirectiveError(key, ErrInvalidFieldName, err)
+		}
+		if field, ok := duplicateField(val); ok {
+			opts.warn(key, "duplicate field name "+field)
+		}
+	}
+	if opts != nil && opts.SplitFieldListOnWhitespace && tokenRequireExtensionFields(key) {
+		if list, ok := splitFieldListOnWhitespace(val); ok {
+			opts.warn(key, "field names separated by whitespace instead of ','")
+			val = list
 		}
 	}
 
+	if opts != nil && opts.TruncateDeltaSeconds && isDeltaSecondsDirective(key) {
+		if digits := leadingDigits(val); digits != "" && digits != val {
+			opts.warn(key, "ignoring "+strconv.Quote(val[len(digits):])+" after the delta-seconds value")
+			val = digits
+		}
+	}
+
+	if err := d.setPair(key, val); err != errCacheExtension {
+		if err == nil {
+			opts.directive(key, val, StandardDirective)
+		}
+		return err
+	}
+
+	if opts != nil && opts.DecodeExtensionValues {
+		decoded, err := url.PathUnescape(val)
+		if err != nil {
+			return newDirectiveError(key, ErrExtensionValueEncoding, err)
+		}
+		if i := strings.IndexFunc(decoded, isInvalidQuotedRune); i >= 0 {
+			return newDirectiveError(key, ErrExtensionValueEncoding, fmt.Errorf("decodes to control character %q", decoded[i]))
+		}
+		d.addExtension(formatExtension(name, decoded))
+		opts.directive(name, decoded, ExtensionDirective)
+		return nil
+	}
+
+	d.addExtension(name + "=" + raw)
+	opts.directive(name, val, ExtensionDirective)
 	return nil
 }
 
@@ -110,68 +170,160 @@ func tokenRequireExtensionFields(token string) bool {
 	}
 }
 
-func parseQuotedString(raw string) (int, string) {
-	if raw[0] != '"' {
-		return -1, ""
+// stripBrackets removes a pair of brackets or braces around val, ignoring
+// surrounding whitespace. ok is false when val is not wrapped in either.
+func stripBrackets(val string) (inner string, ok bool) {
+	val = strings.Trim(val, " \t")
+	if len(val) < 2 {
+		return val, false
+	}
+	switch val[:1] + val[len(val)-1:] {
+	case "[]", "{}":
+		return val[1 : len(val)-1], true
+	}
+	return val, false
+}
+
+// stripTrailingComment splits a parenthesized comment, which may nest, off the
+// end of val. It must be preceded by whitespace or a ',', or be the whole value.
+// ok is false when val does not end with a comment.
+func stripTrailingComment(val string) (rest, comment string, ok bool) {
+	trimmed := strings.TrimRight(val, " \t")
+	if !strings.HasSuffix(trimmed, ")") {
+		return val, "", false
+	}
+
+	depth := 0
+	for i := len(trimmed) - 1; i >= 0; i-- {
+		switch trimmed[i] {
+		case ')':
+			depth++
+		case '(':
+			depth--
+		}
+		if depth > 0 {
+			continue
+		}
+		if i > 0 && !isWhiteSpace(trimmed[i-1]) && trimmed[i-1] != ',' {
+			return val, "", false
+		}
+		return trimmed[:i], strings.TrimSpace(trimmed[i+1 : len(trimmed)-1]), true
+	}
+	return val, "", false
+}
+
+// isDeltaSecondsDirective reports whether the directive takes a delta-seconds
+// value.
+func isDeltaSecondsDirective(token string) bool {
+	switch token {
+	case HeaderMaxAge, HeaderSMaxAge, HeaderMaxStale, HeaderMinFresh,
+		HeaderStaleIfError, HeaderStaleWhileRevalidate:
+		return true
+	default:
+		return false
+	}
+}
+
+// leadingDigits returns the decimal digits at the start of val.
+func leadingDigits(val string) string {
+	end := 0
+	for end < len(val) && val[end] >= '0' && val[end] <= '9' {
+		end++
+	}
+	return val[:end]
+}
+
+// validateFieldList checks that every member of a no-cache or private field
+// list is a field-name token. Empty members are ignored, as recipients of a
+// list must accept them (RFC 9110 Section 5.6.1).
+func validateFieldList(val string) error {
+	for _, field := range strings.Split(val, ",") {
+		field = strings.Trim(field, " \t")
+		if field != "" && !isTokenString(field) {
+			return fmt.Errorf("%q", field)
+		}
 	}
+	return nil
+}
 
-	var (
-		rl     = len(raw)
-		buf    = make([]byte, rl)
-		eat    = 1
-		bufIdx = 0
-	)
+// duplicateField returns the first field name of a field list that repeats an
+// earlier one once canonicalized, as Set-Cookie in `Set-Cookie, set-cookie`.
+func duplicateField(val string) (string, bool) {
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(val, ",") {
+		field = http.CanonicalHeaderKey(strings.Trim(field, " \t"))
+		if field == "" {
+			continue
+		}
+		if seen[field] {
+			return field, true
+		}
+		seen[field] = true
+	}
+	return "", false
+}
 
-	for i := 1; i < rl; i++ {
-		switch b := raw[i]; b {
-		case '"':
-			eat++
-			buf = buf[0:bufIdx]
-			return i + 1, string(buf)
+// splitFieldListOnWhitespace rewrites a field list so that field names
+// separated by spaces or tabs are separated by ',' instead. ok is false when
+// no field name contained whitespace and val is returned unchanged.
+func splitFieldListOnWhitespace(val string) (list string, ok bool) {
+	var fields []string
+	for _, field := range strings.Split(val, ",") {
+		names := strings.FieldsFunc(field, func(r rune) bool { return r == ' ' || r == '\t' })
+		if len(names) > 1 {
+			ok = true
+		}
+		fields = append(fields, names...)
+	}
+	if !ok {
+		return val, false
+	}
+	return strings.Join(fields, ","), true
+}
+
+// parseQuotedString unquotes the quoted-string at the start of raw and returns the
+// number of bytes consumed, or -1 if it is not terminated. A string opened with a
+// single quote, only accepted with ParseOptions.AllowSingleQuotes, ends at the next
+// unescaped single quote and may contain double quotes.
+//
+// obs-text (0x80-0xFF) is part of qdtext and preserved verbatim, both on its own and
+// after a backslash. A quoted-pair stands for the octet after the backslash (RFC 9110
+// Section 5.6.4), so `\n` is the letter n, not a line feed. Control characters other
+// than HTAB cannot appear in a quoted-string, escaped or not, and are handled
+// according to mode; with ErrorOnInvalidQuotedByte, invalid is the offset in raw of
+// the first one, and -1 otherwise.
+func parseQuotedString(raw string, mode InvalidQuotedByteMode) (eaten int, value string, invalid int) {
+	quote := raw[0]
+	if quote != '"' && quote != '\'' {
+		return -1, "", -1
+	}
+
+	buf := make([]byte, 0, len(raw))
+	for i := 1; i < len(raw); i++ {
+		b := raw[i]
+		switch b {
+		case quote:
+			return i + 1, string(buf), -1
 		case '\\':
-			if len(raw) < i+2 {
-				return -1, ""
+			if i+1 == len(raw) {
+				return -1, "", -1
 			}
-
-			buf[bufIdx] = unquotePair(raw[i+1])
 			i++
-			bufIdx++
-			eat += 2
-		default:
-			if isQdText(b) {
-				buf[bufIdx] = b
-			} else {
-				buf[bufIdx] = '?'
+			b = raw[i]
+		}
+
+		// The check applies to the decoded byte, so a backslash does not
+		// smuggle a control character past it.
+		if !isQuotedPairText(b) {
+			switch mode {
+			case ErrorOnInvalidQuotedByte:
+				return -1, "", i
+			case StripInvalidQuotedByte:
+				continue
 			}
-			eat++
-			bufIdx++
+			b = '?'
 		}
+		buf = append(buf, b)
 	}
-	return -1, ""
-}
-
-func unquotePair(b byte) byte {
-	switch b {
-	case 'a':
-		return '\a'
-	case 'b':
-		return '\b'
-	case 'f':
-		return '\f'
-	case 'n':
-		return '\n'
-	case 'r':
-		return '\r'
-	case 't':
-		return '\t'
-	case 'v':
-		return '\v'
-	case '\\':
-		return '\\'
-	case '\'':
-		return '\''
-	case '"':
-		return '"'
-	}
-	return '?'
+	return -1, "", -1
 }
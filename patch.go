@@ -0,0 +1,145 @@
+package cache
+
+import "net/http"
+
+// DirectivePatch declaratively describes a rewrite of response directives, the
+// kind CDNs and policy engines configure ("drop no-store, cap max-age at 60"),
+// as an alternative to mutating fields by hand. The zero value changes
+// nothing. See (*ResponseCacheDirective).Apply.
+type DirectivePatch struct {
+	// SetMaxAge, SetSMaxAge, SetStaleWhileRevalidate and SetStaleIfError set
+	// the delta-seconds directives they name, when not nil. A negative value
+	// removes the directive.
+	SetMaxAge, SetSMaxAge, SetStaleWhileRevalidate, SetStaleIfError *int32
+
+	// ClearMaxAge, ClearSMaxAge, ClearStaleWhileRevalidate and
+	// ClearStaleIfError remove the delta-seconds directives they name.
+	ClearMaxAge, ClearSMaxAge, ClearStaleWhileRevalidate, ClearStaleIfError bool
+
+	// SetPublic, SetNoStore, SetMustRevalidate, SetProxyRevalidate,
+	// SetNoTransform and SetImmutable add the directives they name.
+	SetPublic, SetNoStore, SetMustRevalidate, SetProxyRevalidate, SetNoTransform, SetImmutable bool
+
+	// ClearPublic, ClearNoStore, ClearMustRevalidate, ClearProxyRevalidate,
+	// ClearNoTransform and ClearImmutable remove the directives they name.
+	ClearPublic, ClearNoStore, ClearMustRevalidate, ClearProxyRevalidate, ClearNoTransform, ClearImmutable bool
+
+	// SetNoCache and SetPrivate add the directive they name, bare unless
+	// fields are added as well or the directive already had a field list.
+	SetNoCache, SetPrivate bool
+
+	// AddNoCacheFields and AddPrivateFields add field names to the no-cache
+	// and private field lists, adding the directive if needed. Names are
+	// canonicalized with http.CanonicalHeaderKey. A bare directive stays bare,
+	// as when parsing: the names are then only recorded in NoCacheRaw or
+	// PrivateRaw.
+	AddNoCacheFields, AddPrivateFields []string
+
+	// ClearNoCache and ClearPrivate remove the directive they name together
+	// with its field list.
+	ClearNoCache, ClearPrivate bool
+
+	// RemoveExtensions removes the extensions with these names, compared
+	// case-insensitively, see RemoveExtension.
+	RemoveExtensions []string
+
+	// AddExtensions appends these raw extensions, `name` or `name=value`.
+	AddExtensions []string
+}
+
+// Apply rewrites the directive according to patch. Removals are applied
+// before additions, so a patch that both clears and sets a directive sets it,
+// and a patch may replace an extension by removing and adding it. The presence
+// flags of no-cache, private and the delta-seconds directives are kept in sync
+// with their values.
+func (directive *ResponseCacheDirective) Apply(patch DirectivePatch) {
+	clearDeltaSeconds(&directive.MaxAge, &directive.MaxAgePresent, patch.ClearMaxAge)
+	clearDeltaSeconds(&directive.SMaxAge, &directive.SMaxAgePresent, patch.ClearSMaxAge)
+	clearDeltaSeconds(&directive.StaleWhileRevalidate, &directive.StaleWhileRevalidatePresent, patch.ClearStaleWhileRevalidate)
+	clearDeltaSeconds(&directive.StaleIfError, &directive.StaleIfErrorPresent, patch.ClearStaleIfError)
+
+	clearFlag(&directive.Public, patch.ClearPublic)
+	clearFlag(&directive.NoStore, patch.ClearNoStore)
+	clearFlag(&directive.MustRevalidate, patch.ClearMustRevalidate)
+	clearFlag(&directive.ProxyRevalidate, patch.ClearProxyRevalidate)
+	clearFlag(&directive.NoTransform, patch.ClearNoTransform)
+	clearFlag(&directive.Immutable, patch.ClearImmutable)
+
+	if patch.ClearNoCache {
+		directive.NoCache, directive.NoCachePresent, directive.NoCacheRaw = nil, false, nil
+	}
+	if patch.ClearPrivate {
+		directive.Private, directive.PrivatePresent, directive.PrivateRaw = nil, false, nil
+	}
+
+	for _, name := range patch.RemoveExtensions {
+		directive.RemoveExtension(name)
+	}
+
+	setDeltaSeconds(&directive.MaxAge, &directive.MaxAgePresent, patch.SetMaxAge)
+	setDeltaSeconds(&directive.SMaxAge, &directive.SMaxAgePresent, patch.SetSMaxAge)
+	setDeltaSeconds(&directive.StaleWhileRevalidate, &directive.StaleWhileRevalidatePresent, patch.SetStaleWhileRevalidate)
+	setDeltaSeconds(&directive.StaleIfError, &directive.StaleIfErrorPresent, patch.SetStaleIfError)
+
+	directive.Public = directive.Public || patch.SetPublic
+	directive.NoStore = directive.NoStore || patch.SetNoStore
+	directive.MustRevalidate = directive.MustRevalidate || patch.SetMustRevalidate
+	directive.ProxyRevalidate = directive.ProxyRevalidate || patch.SetProxyRevalidate
+	directive.NoTransform = directive.NoTransform || patch.SetNoTransform
+	directive.Immutable = directive.Immutable || patch.SetImmutable
+
+	if patch.SetNoCache || len(patch.AddNoCacheFields) > 0 {
+		bare := directive.NoCachePresent && len(directive.NoCache) == 0
+		directive.NoCachePresent = true
+		directive.NoCache, directive.NoCacheRaw = addFields(directive.NoCache, directive.NoCacheRaw, patch.AddNoCacheFields, bare)
+	}
+	if patch.SetPrivate || len(patch.AddPrivateFields) > 0 {
+		bare := directive.PrivatePresent && len(directive.Private) == 0
+		directive.PrivatePresent = true
+		directive.Private, directive.PrivateRaw = addFields(directive.Private, directive.PrivateRaw, patch.AddPrivateFields, bare)
+	}
+
+	directive.Extensions = append(directive.Extensions, patch.AddExtensions...)
+}
+
+func clearDeltaSeconds(delta *int32, present *bool, remove bool) {
+	if remove {
+		*delta, *present = 0, false
+	}
+}
+
+func setDeltaSeconds(delta *int32, present *bool, value *int32) {
+	if value == nil {
+		return
+	}
+	if *value < 0 {
+		*delta, *present = 0, false
+		return
+	}
+	*delta, *present = *value, true
+}
+
+func clearFlag(flag *bool, remove bool) {
+	if remove {
+		*flag = false
+	}
+}
+
+// addFields adds names to a field list. When the directive is bare, the names
+// only go to raw, so that the bare directive keeps covering every field.
+func addFields(fields map[string]bool, raw []string, names []string, bare bool) (map[string]bool, []string) {
+	if len(names) == 0 {
+		return fields, raw
+	}
+	if bare {
+		return fields, append(raw, names...)
+	}
+	if fields == nil {
+		fields = make(map[string]bool, len(names))
+	}
+	for _, name := range names {
+		raw = append(raw, name)
+		fields[http.CanonicalHeaderKey(name)] = true
+	}
+	return fields, raw
+}
@@ -0,0 +1,92 @@
+package cache
+
+import "testing"
+
+func TestApplyKeepsBareFieldListBare(t *testing.T) {
+	directive, err := NewResponseCacheDirective("no-cache, private")
+	if err != nil {
+		t.Fatal(err)
+	}
+	directive.Apply(DirectivePatch{
+		AddNoCacheFields: []string{"Set-Cookie"},
+		AddPrivateFields: []string{"Authorization"},
+	})
+	if got, want := directive.String(), "private, no-cache"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if !directive.NoCacheContains("X-Other") || !directive.PrivateContains("X-Other") {
+		t.Error("adding fields narrowed a bare directive")
+	}
+	if len(directive.NoCacheRaw) != 1 || len(directive.PrivateRaw) != 1 {
+		t.Errorf("raw field names = %q, %q, want one each", directive.NoCacheRaw, directive.PrivateRaw)
+	}
+}
+
+func TestApply(t *testing.T) {
+	sixty, removed := int32(60), int32(-1)
+	tests := []struct {
+		name  string
+		value string
+		patch DirectivePatch
+		want  string
+	}{
+		{"zero patch", "public, max-age=300", DirectivePatch{}, "public, max-age=300"},
+		{"cap max-age", "public, max-age=300", DirectivePatch{SetMaxAge: &sixty}, "public, max-age=60"},
+		{"remove with negative", "max-age=300, s-maxage=600", DirectivePatch{SetSMaxAge: &removed}, "max-age=300"},
+		{"drop no-store", "no-store, max-age=0", DirectivePatch{ClearNoStore: true, ClearMaxAge: true, SetPublic: true}, "public"},
+		{"clear then set", "max-age=300", DirectivePatch{ClearMaxAge: true, SetMaxAge: &sixty}, "max-age=60"},
+		{"add no-cache fields", "max-age=60", DirectivePatch{AddNoCacheFields: []string{"set-cookie"}}, `no-cache="Set-Cookie", max-age=60`},
+		{"extend field list", `private="X-Id"`, DirectivePatch{AddPrivateFields: []string{"Authorization"}}, `private="Authorization, X-Id"`},
+		{"set bare no-cache", "max-age=60", DirectivePatch{SetNoCache: true}, "no-cache, max-age=60"},
+		{"clear private", `private="X-Id", max-age=60`, DirectivePatch{ClearPrivate: true}, "max-age=60"},
+		{"replace extension", "max-age=60, cdn=a", DirectivePatch{RemoveExtensions: []string{"CDN"}, AddExtensions: []string{"cdn=b"}}, "max-age=60, cdn=b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			directive, err := NewResponseCacheDirective(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			directive.Apply(tt.patch)
+			got := directive.String()
+			if got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+
+			again, err := NewResponseCacheDirective(got)
+			if err != nil {
+				t.Fatalf("NewResponseCacheDirective(%q): %v", got, err)
+			}
+			if !again.Equal(directive) {
+				t.Errorf("%q does not parse back to the patched directive", got)
+			}
+		})
+	}
+}
+
+func TestApplyPresenceFlags(t *testing.T) {
+	zero, removed := int32(0), int32(-1)
+
+	var directive ResponseCacheDirective
+	directive.Apply(DirectivePatch{SetMaxAge: &zero, SetStaleIfError: &zero})
+	if !directive.MaxAgePresent || directive.MaxAge != 0 || !directive.StaleIfErrorPresent || directive.SMaxAgePresent {
+		t.Errorf("zero value patched with max-age=0 and stale-if-error=0 = %+v", directive)
+	}
+	if got, want := directive.String(), "max-age=0, stale-if-error=0"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	directive.Apply(DirectivePatch{ClearMaxAge: true, SetStaleIfError: &removed})
+	if directive.MaxAgePresent || directive.StaleIfErrorPresent || !directive.IsZero() {
+		t.Errorf("cleared directive = %+v, want it zero", directive)
+	}
+
+	directive.Apply(DirectivePatch{SetNoCache: true, SetPrivate: true})
+	if !directive.NoCachePresent || len(directive.NoCache) != 0 || !directive.PrivatePresent || len(directive.Private) != 0 {
+		t.Errorf("bare no-cache and private = %+v", directive)
+	}
+	directive.Apply(DirectivePatch{ClearNoCache: true, ClearPrivate: true})
+	if directive.NoCachePresent || directive.PrivatePresent || !directive.IsZero() {
+		t.Errorf("cleared no-cache and private = %+v, want it zero", directive)
+	}
+}
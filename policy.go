@@ -0,0 +1,114 @@
+package cache
+
+// Policy is the caching behaviour a cache should enforce when answering one
+// particular request from one particular stored response. See EffectivePolicy.
+type Policy struct {
+	// UseCache reports whether the stored response may be used to answer the
+	// request at all, possibly after revalidation. When it is false the
+	// request must be forwarded to the origin.
+	UseCache bool
+
+	// Revalidate reports whether every reuse requires a successful
+	// revalidation with the origin, whatever the age of the response.
+	Revalidate bool
+
+	// MaxUsableAge is the greatest age, in seconds, at which the response may
+	// be served without revalidation. It is negative when no age is
+	// acceptable, and -1 as well when the response has no explicit lifetime:
+	// heuristic freshness is left to the caller, see EffectiveFreshnessWindow.
+	MaxUsableAge int32
+
+	// ServeStaleAllowed reports whether the directives allow serving the
+	// response once stale, for instance within stale-while-revalidate or
+	// stale-if-error or while the origin is unreachable (RFC 9111
+	// Section 4.2.4).
+	ServeStaleAllowed bool
+}
+
+// EffectivePolicy combines the request and response directives into the
+// policy a cache of the given type should enforce for this request. It is a
+// one-call decision built on Uncacheable, EffectiveFreshnessWindow and the
+// other predicates of this package, which stay available for callers that need
+// different trade-offs.
+//
+// The request can only make the policy stricter: its max-stale never lets a
+// response be served stale against must-revalidate, proxy-revalidate or
+// s-maxage (RFC 9111 Section 5.2.2.2), and no-cache on either side forces
+// revalidation. A no-cache response directive with a field list only restricts
+// the listed fields and does not force revalidation of the response as a whole.
+func EffectivePolicy(req *RequestCacheDirective, resp *ResponseCacheDirective, shared bool) Policy {
+	if resp.Uncacheable(shared) {
+		return Policy{MaxUsableAge: -1}
+	}
+
+	policy := Policy{
+		UseCache:   true,
+		Revalidate: req.ForcesRevalidation() || (resp.NoCachePresent && len(resp.NoCache) == 0),
+	}
+	policy.ServeStaleAllowed = !policy.Revalidate && !resp.staleForbidden(shared)
+
+	lifetime := resp.lifetime(shared)
+	switch {
+	case policy.Revalidate, lifetime < 0:
+		policy.MaxUsableAge = -1
+	case policy.ServeStaleAllowed:
+		policy.MaxUsableAge = req.EffectiveFreshnessWindow(lifetime)
+	default:
+		window := *req
+		window.MaxStale, window.MaxStaleAny = -1, false
+		policy.MaxUsableAge = window.EffectiveFreshnessWindow(lifetime)
+	}
+	return policy
+}
+
+// Decision is the outcome of EvaluateResponse for a stored response.
+type Decision struct {
+	// Store reports whether the cache may store the response, see
+	// Uncacheable.
+	Store bool
+
+	// Lifetime is the explicit freshness lifetime resolved for the cache
+	// type, or -1 when the response has none.
+	Lifetime int32
+
+	// Reuse reports whether the response may be served as is: it is storable
+	// and fresh, and nothing requires revalidation.
+	Reuse bool
+
+	// Revalidate reports whether a conditional request must be sent to the
+	// origin before reuse, see NeedsConditionalRequest.
+	Revalidate bool
+
+	// ServeStaleWhileRevalidate and ServeStaleOnError report whether the
+	// stale response may be served while revalidating in the background, or
+	// when the origin fails, within the RFC 5861 windows.
+	ServeStaleWhileRevalidate, ServeStaleOnError bool
+}
+
+// EvaluateResponse parses a response Cache-Control value and decides, in one
+// call, what a cache of the given type should do with the response at the
+// given age. It is the convenience entry point for simple caches; the
+// predicates it is built on stay available for finer control. The parse error
+// is returned when headerValue is malformed.
+//
+// Without an explicit lifetime, Reuse and Revalidate are both false: freshness
+// then depends on Expires or heuristics, which are left to the caller.
+func EvaluateResponse(headerValue string, ageSeconds int32, shared bool) (Decision, error) {
+	resp, err := NewResponseCacheDirective(headerValue)
+	if err != nil {
+		return Decision{}, err
+	}
+	if resp.Uncacheable(shared) {
+		return Decision{Lifetime: -1}, nil
+	}
+
+	d := Decision{
+		Store:      true,
+		Lifetime:   resp.lifetime(shared),
+		Revalidate: resp.NeedsConditionalRequest(ageSeconds, shared),
+	}
+	d.Reuse = d.Lifetime >= 0 && !d.Revalidate
+	d.ServeStaleWhileRevalidate = resp.canServeStale(ageSeconds, shared, resp.staleWhileRevalidate())
+	d.ServeStaleOnError = resp.canServeStale(ageSeconds, shared, resp.staleIfError())
+	return d, nil
+}
@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEffectivePolicy(t *testing.T) {
+	tests := []struct {
+		req    string
+		resp   string
+		shared bool
+		want   Policy
+	}{
+		{"", "max-age=60", false, Policy{UseCache: true, MaxUsableAge: 59, ServeStaleAllowed: true}},
+		{"", "public", true, Policy{UseCache: true, MaxUsableAge: -1, ServeStaleAllowed: true}},
+		{"", "no-store, max-age=60", false, Policy{MaxUsableAge: -1}},
+		{"", "private, max-age=60", true, Policy{MaxUsableAge: -1}},
+		{"", "private, max-age=60", false, Policy{UseCache: true, MaxUsableAge: 59, ServeStaleAllowed: true}},
+		{"", "max-age=60, s-maxage=120", true, Policy{UseCache: true, MaxUsableAge: 119}},
+		{"", "max-age=60, s-maxage=120", false, Policy{UseCache: true, MaxUsableAge: 59, ServeStaleAllowed: true}},
+		{"", "no-cache, max-age=60", false, Policy{UseCache: true, Revalidate: true, MaxUsableAge: -1}},
+		{"", `no-cache="Set-Cookie", max-age=60`, false, Policy{UseCache: true, MaxUsableAge: 59}},
+		{"no-cache", "max-age=60", false, Policy{UseCache: true, Revalidate: true, MaxUsableAge: -1}},
+		{"max-age=10", "max-age=60, stale-while-revalidate=30", false, Policy{UseCache: true, MaxUsableAge: 10, ServeStaleAllowed: true}},
+		{"min-fresh=20", "max-age=60", false, Policy{UseCache: true, MaxUsableAge: 40, ServeStaleAllowed: true}},
+
+		// max-stale cannot override what forbids serving stale.
+		{"max-stale=30", "max-age=60", false, Policy{UseCache: true, MaxUsableAge: 90, ServeStaleAllowed: true}},
+		{"max-stale=30", "max-age=60, must-revalidate", false, Policy{UseCache: true, MaxUsableAge: 59}},
+		{"max-stale=30", "max-age=60, proxy-revalidate", true, Policy{UseCache: true, MaxUsableAge: 59}},
+		{"max-stale=30", "max-age=60, proxy-revalidate", false, Policy{UseCache: true, MaxUsableAge: 90, ServeStaleAllowed: true}},
+		{"max-stale", "s-maxage=60", true, Policy{UseCache: true, MaxUsableAge: 59}},
+	}
+	for _, tt := range tests {
+		req, err := NewRequestCacheDirective(tt.req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := NewResponseCacheDirective(tt.resp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := EffectivePolicy(req, resp, tt.shared); got != tt.want {
+			t.Errorf("EffectivePolicy(%q, %q, %v) = %+v, want %+v", tt.req, tt.resp, tt.shared, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateResponse(t *testing.T) {
+	tests := []struct {
+		header string
+		age    int32
+		shared bool
+		want   Decision
+	}{
+		{"max-age=60", 30, false, Decision{Store: true, Lifetime: 60, Reuse: true}},
+		{"max-age=60", 60, false, Decision{Store: true, Lifetime: 60, Revalidate: true}},
+		{"public", 0, true, Decision{Store: true, Lifetime: -1}},
+		{"no-store, max-age=60", 0, false, Decision{Lifetime: -1}},
+		{"private, max-age=60", 0, true, Decision{Lifetime: -1}},
+		{"private, max-age=60", 0, false, Decision{Store: true, Lifetime: 60, Reuse: true}},
+		{"no-cache, max-age=60", 0, false, Decision{Store: true, Lifetime: 60, Revalidate: true}},
+		{"s-maxage=60, max-age=10", 30, true, Decision{Store: true, Lifetime: 60, Reuse: true}},
+		{"s-maxage=60, max-age=10", 30, false, Decision{Store: true, Lifetime: 10, Revalidate: true}},
+
+		// stale-* windows start when the response becomes stale.
+		{"max-age=60, stale-while-revalidate=30", 70, false, Decision{Store: true, Lifetime: 60, Revalidate: true, ServeStaleWhileRevalidate: true}},
+		{"max-age=60, stale-while-revalidate=30", 100, false, Decision{Store: true, Lifetime: 60, Revalidate: true}},
+		{"max-age=60, stale-if-error=300", 30, false, Decision{Store: true, Lifetime: 60, Reuse: true}},
+		{"max-age=60, stale-if-error=300", 200, false, Decision{Store: true, Lifetime: 60, Revalidate: true, ServeStaleOnError: true}},
+		{"max-age=60, stale-if-error=300, must-revalidate", 200, false, Decision{Store: true, Lifetime: 60, Revalidate: true}},
+		{"s-maxage=60, stale-if-error=300", 200, true, Decision{Store: true, Lifetime: 60, Revalidate: true}},
+		{"max-age=60, stale-if-error=300, proxy-revalidate", 200, false, Decision{Store: true, Lifetime: 60, Revalidate: true, ServeStaleOnError: true}},
+	}
+	for _, tt := range tests {
+		got, err := EvaluateResponse(tt.header, tt.age, tt.shared)
+		if err != nil {
+			t.Errorf("EvaluateResponse(%q, %d, %v): %v", tt.header, tt.age, tt.shared, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("EvaluateResponse(%q, %d, %v) = %+v, want %+v", tt.header, tt.age, tt.shared, got, tt.want)
+		}
+	}
+
+	if _, err := EvaluateResponse("max-age=abc", 0, false); !errors.Is(err, ErrMaxAgeDeltaSeconds) {
+		t.Errorf("EvaluateResponse(%q) = %v, want ErrMaxAgeDeltaSeconds", "max-age=abc", err)
+	}
+}
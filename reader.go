@@ -0,0 +1,33 @@
+package cache
+
+import (
+	"errors"
+	"io"
+)
+
+// MaxReaderValueSize is the largest Cache-Control value ParseResponseReader
+// accepts. Real-world values are a few hundred bytes at most.
+const MaxReaderValueSize = 64 << 10
+
+var (
+	ErrValueTooLarge = errors.New("Cache-Control value exceeds MaxReaderValueSize")
+)
+
+// ParseResponseReader parses a response Cache-Control value read from r until
+// io.EOF. The value is not parsed incrementally: it is read into memory in
+// full and then handed to NewResponseCacheDirective, so tokens and
+// quoted-strings split across reads parse exactly as if the value had been
+// passed as a string. What bounds the buffering is the cap: it never reads
+// more than MaxReaderValueSize+1 bytes from r and returns ErrValueTooLarge
+// when the value does not fit, so a hostile peer cannot make it hold an
+// unbounded amount of data.
+func ParseResponseReader(r io.Reader) (*ResponseCacheDirective, error) {
+	value, err := io.ReadAll(io.LimitReader(r, MaxReaderValueSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(value) > MaxReaderValueSize {
+		return nil, ErrValueTooLarge
+	}
+	return NewResponseCacheDirective(string(value))
+}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestParseResponseReader(t *testing.T) {
+	const value = `public, max-age=60, no-cache="Set-Cookie, X-Id", ext="a, b"`
+	want, err := NewResponseCacheDirective(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// One byte per read splits every token and quoted-string.
+	got, err := ParseResponseReader(iotest.OneByteReader(strings.NewReader(value)))
+	if err != nil {
+		t.Fatalf("ParseResponseReader(OneByteReader(%q)): %v", value, err)
+	}
+	if !got.Equal(want) {
+		t.Errorf("ParseResponseReader(OneByteReader(%q)) = %q, want %q", value, got, want)
+	}
+
+	got, err = ParseResponseReader(iotest.HalfReader(strings.NewReader(value)))
+	if err != nil || !got.Equal(want) {
+		t.Errorf("ParseResponseReader(HalfReader(%q)) = %q, %v, want %q", value, got, err, want)
+	}
+}
+
+func TestParseResponseReaderLimit(t *testing.T) {
+	fits := "max-age=60," + strings.Repeat(" ", MaxReaderValueSize-len("max-age=60,"))
+	directive, err := ParseResponseReader(strings.NewReader(fits))
+	if err != nil || !directive.MaxAgePresent || directive.MaxAge != 60 {
+		t.Errorf("ParseResponseReader(%d bytes) = %v, %v, want max-age=60", len(fits), directive, err)
+	}
+
+	if _, err := ParseResponseReader(strings.NewReader(fits + " ")); !errors.Is(err, ErrValueTooLarge) {
+		t.Errorf("ParseResponseReader(%d bytes) = %v, want ErrValueTooLarge", len(fits)+1, err)
+	}
+
+	// A value split into one-byte reads hits the cap the same way.
+	if _, err := ParseResponseReader(iotest.OneByteReader(strings.NewReader(fits + " "))); !errors.Is(err, ErrValueTooLarge) {
+		t.Errorf("ParseResponseReader(OneByteReader(%d bytes)) = %v, want ErrValueTooLarge", len(fits)+1, err)
+	}
+}
+
+func TestParseResponseReaderError(t *testing.T) {
+	readErr := errors.New("connection reset")
+	if _, err := ParseResponseReader(iotest.ErrReader(readErr)); !errors.Is(err, readErr) {
+		t.Errorf("ParseResponseReader(ErrReader) = %v, want %v", err, readErr)
+	}
+}
@@ -0,0 +1,224 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DirectiveScanner iterates over the raw directives of a Cache-Control value
+// without interpreting them. It is the building block underneath
+// NewRequestCacheDirective and NewResponseCacheDirective, for callers that
+// want to implement their own directive handling:
+//
+//	s := NewDirectiveScanner(value)
+//	for s.Scan() {
+//		name, value, hasValue := s.Directive()
+//		...
+//	}
+//	if err := s.Err(); err != nil {
+//		...
+//	}
+//
+// Scanning stops at the first malformed directive, whose error Err returns.
+type DirectiveScanner struct {
+	val   string
+	index int
+
+	// strict ends unquoted no-cache and private values at ',' like any other
+	// value, see ParseOptions.Strict.
+	strict bool
+
+	// singleQuotes also accepts values quoted with ', see
+	// ParseOptions.AllowSingleQuotes. singleQuoted reports whether the value
+	// of the current directive was.
+	singleQuotes, singleQuoted bool
+
+	// invalidQuotedByte is ParseOptions.OnInvalidQuotedByte.
+	invalidQuotedByte InvalidQuotedByteMode
+
+	// extendedWhitespace also treats VT and FF as whitespace, see
+	// ParseOptions.AllowExtendedWhitespace. sawExtendedWhitespace reports
+	// whether any was skipped.
+	extendedWhitespace, sawExtendedWhitespace bool
+
+	// name is the directive name as written, lower its lower-cased form.
+	name, lower string
+
+	// value is the unquoted value, raw the value as written, quotes included.
+	value, raw string
+	hasValue   bool
+
+	err error
+}
+
+// NewDirectiveScanner returns a scanner over the directives of value.
+func NewDirectiveScanner(value string) *DirectiveScanner {
+	return &DirectiveScanner{val: value}
+}
+
+// Scan advances to the next directive. It returns false at the end of the
+// value or on error.
+//
+// Leading whitespace and commas are skipped. The directive name runs up to
+// the first byte that is not a token character (RFC 9110 Section 5.6.2). If
+// it is followed by '=', the value is either a quoted-string, unquoted with
+// parseQuotedString, or runs up to the next whitespace or ','. Unquoted
+// no-cache and private values also swallow commas, for origins that send
+// field lists without quotes, up to a ',' followed by a known directive or a
+// name=value pair.
+//
+// Bytes 0x80-0xFF (obs-text) are only accepted inside quoted strings, where
+// they are kept verbatim without any UTF-8 validation. They are not tchar, so
+// a directive name or an unquoted value containing one is rejected with a
+// SyntaxError instead of being split into meaningless single-byte tokens.
+// Control characters other than HTAB are rejected the same way outside
+// quoted strings, so that a CR or LF can never reach a serialized header.
+func (s *DirectiveScanner) Scan() bool {
+	if s.err != nil {
+		return false
+	}
+
+	var (
+		val   = s.val
+		vl    = len(val)
+		index = s.index
+	)
+
+	for index < vl && (s.isWhiteSpace(val[index]) || val[index] == ',') {
+		index++
+	}
+	if index == vl {
+		s.index = index
+		return false
+	}
+	if isCtl(val[index]) {
+		return s.fail(&SyntaxError{Offset: index, Msg: fmt.Sprintf("control character %q", val[index])})
+	}
+
+	// A '=' where a directive name should start means the name is empty
+	if val[index] == '=' {
+		return s.fail(ErrEmptyDirectiveName)
+	}
+
+	// Find the end of the token
+	tokenEnd := index + 1
+	for tokenEnd < vl && isToken(val[tokenEnd]) {
+		tokenEnd++
+	}
+	if isObsText(val[index]) {
+		return s.fail(&SyntaxError{Offset: index, Msg: "non-ASCII byte in directive name"})
+	}
+	if tokenEnd < vl && isObsText(val[tokenEnd]) {
+		return s.fail(&SyntaxError{Offset: tokenEnd, Msg: "non-ASCII byte in directive name"})
+	}
+
+	s.name = val[index:tokenEnd]
+	s.lower = strings.ToLower(s.name)
+	s.value, s.raw, s.hasValue, s.singleQuoted = "", "", false, false
+
+	// If the token doesn't have an equals sign, it's a simple token
+	if tokenEnd == vl || val[tokenEnd] != '=' {
+		s.index = tokenEnd
+		return true
+	}
+
+	s.hasValue = true
+	valueStart := tokenEnd + 1
+
+	// If the value is quoted, parse the quoted string
+	if valueStart < vl && (val[valueStart] == '"' || (s.singleQuotes && val[valueStart] == '\'')) {
+		eaten, value, invalid := parseQuotedString(val[valueStart:], s.invalidQuotedByte)
+		if invalid >= 0 {
+			offset := valueStart + invalid
+			return s.fail(&SyntaxError{Offset: offset, Msg: fmt.Sprintf("invalid byte %q in quoted-string", val[offset])})
+		}
+		if eaten == -1 {
+			return s.fail(ErrMissingClosingQuote)
+		}
+		s.index = valueStart + eaten
+		s.value, s.raw = value, val[valueStart:s.index]
+
+		// A single-quoted value is kept as if it had been double-quoted, and a
+		// value with substituted or stripped bytes as it now reads
+		s.singleQuoted = val[valueStart] == '\''
+		if s.singleQuoted || strings.IndexFunc(s.raw, isInvalidQuotedRune) >= 0 {
+			s.raw = string(appendQuotedString(nil, value))
+		}
+		return true
+	}
+
+	// If the value is not quoted, find the end of the pair value
+	requireExtensionField := !s.strict && tokenRequireExtensionFields(resolveAlias(s.lower))
+	valueEnd := valueStart
+	for valueEnd < vl {
+		if s.isWhiteSpace(val[valueEnd]) ||
+			(val[valueEnd] == ',' && (!requireExtensionField || startsDirective(val[valueEnd+1:]))) {
+			break
+		}
+		if isObsText(val[valueEnd]) {
+			return s.fail(&SyntaxError{Offset: valueEnd, Msg: "non-ASCII byte in unquoted value"})
+		}
+		if isCtl(val[valueEnd]) {
+			return s.fail(&SyntaxError{Offset: valueEnd, Msg: fmt.Sprintf("control character %q in unquoted value", val[valueEnd])})
+		}
+		valueEnd++
+	}
+	s.index = valueEnd
+
+	// Remove the trailing comma if there is one
+	value := val[valueStart:valueEnd]
+	if value != "" && value[len(value)-1] == ',' {
+		value = value[:len(value)-1]
+	}
+	s.value, s.raw = value, value
+	return true
+}
+
+// startsDirective reports whether val, the rest of an unquoted field list after
+// a ',', starts with what must be the next directive rather than another field
+// name: a known directive or a name=value pair. It keeps
+// `no-cache=Set-Cookie,max-age=60` from swallowing max-age.
+func startsDirective(val string) bool {
+	end := 0
+	for end < len(val) && isToken(val[end]) {
+		end++
+	}
+	if end < len(val) && val[end] == '=' {
+		return true
+	}
+	return isKnownDirective(resolveAlias(strings.ToLower(val[:end])))
+}
+
+// Directive returns the directive found by the last call to Scan: its
+// lower-cased name, its unquoted value and whether it had a value at all.
+func (s *DirectiveScanner) Directive() (name, value string, hasValue bool) {
+	return s.lower, s.value, s.hasValue
+}
+
+// Err returns the error that stopped Scan, if any.
+func (s *DirectiveScanner) Err() error {
+	return s.err
+}
+
+// isInvalidQuotedRune reports whether r is a control character that cannot
+// appear in a quoted-string, and so must not be kept in a raw value.
+func isInvalidQuotedRune(r rune) bool {
+	return r < 0x80 && r != '\t' && isCtl(byte(r))
+}
+
+// isWhiteSpace reports whether c is OWS, or VT or FF with extendedWhitespace.
+func (s *DirectiveScanner) isWhiteSpace(c byte) bool {
+	if isWhiteSpace(c) {
+		return true
+	}
+	if s.extendedWhitespace && isExtendedWhiteSpace(c) {
+		s.sawExtendedWhitespace = true
+		return true
+	}
+	return false
+}
+
+func (s *DirectiveScanner) fail(err error) bool {
+	s.err = err
+	return false
+}
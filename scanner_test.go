@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNonASCIIBytes(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		ext    string
+		offset int
+	}{
+		{"utf-8 in quoted value", `ext="café"`, `ext="caf` + "é" + `"`, -1},
+		{"invalid utf-8 in quoted value", "ext=\"a\xff\xfeb\"", "ext=\"a\xff\xfeb\"", -1},
+		{"utf-8 in unquoted value", "ext=café", "", 7},
+		{"utf-8 in directive name", "café=1", "", 3},
+		{"leading non-ASCII byte", "\xffext", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			directive, err := NewResponseCacheDirective(tt.value)
+			if tt.offset >= 0 {
+				var syntaxErr *SyntaxError
+				if !errors.As(err, &syntaxErr) || syntaxErr.Offset != tt.offset {
+					t.Fatalf("NewResponseCacheDirective(%q) = %v, want a SyntaxError at offset %d", tt.value, err, tt.offset)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(directive.Extensions) != 1 || directive.Extensions[0] != tt.ext {
+				t.Errorf("Extensions = %q, want [%q]", directive.Extensions, tt.ext)
+			}
+		})
+	}
+}
+
+func TestObsTextInQuotedFieldList(t *testing.T) {
+	directive, err := NewResponseCacheDirective("no-cache=\"X-\xe9t\xe9, Set-Cookie\", private=\"X-\\\xff\"")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !directive.NoCache["X-\xe9t\xe9"] || !directive.NoCache["Set-Cookie"] {
+		t.Errorf("NoCache = %q, want X-\\xe9t\\xe9 and Set-Cookie", sortedFields(directive.NoCache))
+	}
+	if !directive.Private["X-\xff"] {
+		t.Errorf("Private = %q, want X-\\xff from a quoted-pair", sortedFields(directive.Private))
+	}
+}
+
+func TestParseQuotedStringObsText(t *testing.T) {
+	tests := []struct {
+		raw   string
+		value string
+	}{
+		{"\"\x80\xff\"", "\x80\xff"},
+		{"\"a\\\x80b\"", "a\x80b"},
+		{"\"\xc3\xa9\"", "\xc3\xa9"},
+	}
+	for _, tt := range tests {
+		eaten, value, invalid := parseQuotedString(tt.raw, SubstituteInvalidQuotedByte)
+		if eaten != len(tt.raw) || value != tt.value || invalid != -1 {
+			t.Errorf("parseQuotedString(%q) = %d, %q, %d, want %d, %q, -1", tt.raw, eaten, value, invalid, len(tt.raw), tt.value)
+		}
+	}
+}
+
+func TestEmptyDirectiveName(t *testing.T) {
+	for _, value := range []string{"=60", "=", "max-age=60,=", "max-age=60, =1", `="x"`} {
+		if _, err := NewResponseCacheDirective(value); !errors.Is(err, ErrEmptyDirectiveName) {
+			t.Errorf("NewResponseCacheDirective(%q) = %v, want ErrEmptyDirectiveName", value, err)
+		}
+		if _, err := NewRequestCacheDirective(value); !errors.Is(err, ErrEmptyDirectiveName) {
+			t.Errorf("NewRequestCacheDirective(%q) = %v, want ErrEmptyDirectiveName", value, err)
+		}
+	}
+}
+
+func TestAllowExtendedWhitespace(t *testing.T) {
+	tests := []struct {
+		value  string
+		offset int
+	}{
+		{"max-age=60,\fpublic", 11},
+		{"max-age=60\v, public", 10},
+		{"\fmax-age=60, public\f", 0},
+	}
+	for _, tt := range tests {
+		var syntaxErr *SyntaxError
+		if _, err := NewResponseCacheDirective(tt.value); !errors.As(err, &syntaxErr) || syntaxErr.Offset != tt.offset {
+			t.Errorf("NewResponseCacheDirective(%q) = %v, want a SyntaxError at offset %d", tt.value, err, tt.offset)
+		}
+		if _, err := ParseResponse(tt.value, &ParseOptions{Strict: true}); !errors.As(err, &syntaxErr) {
+			t.Errorf("strict: ParseResponse(%q) = %v, want a SyntaxError", tt.value, err)
+		}
+
+		var warnings []Warning
+		opts := &ParseOptions{AllowExtendedWhitespace: true, OnWarning: func(w Warning) { warnings = append(warnings, w) }}
+		directive, err := ParseResponse(tt.value, opts)
+		if err != nil {
+			t.Errorf("lenient: ParseResponse(%q): %v", tt.value, err)
+			continue
+		}
+		if got := directive.String(); got != "public, max-age=60" {
+			t.Errorf("lenient: ParseResponse(%q) = %q, want %q", tt.value, got, "public, max-age=60")
+		}
+		if len(warnings) != 1 {
+			t.Errorf("lenient: ParseResponse(%q) warnings = %v, want one", tt.value, warnings)
+		}
+	}
+
+	// CR and LF could split the header, so they are rejected in every mode.
+	for _, value := range []string{"max-age=60,\rpublic", "max-age=60,\r\npublic", "max-age=60\n, public"} {
+		for _, opts := range []*ParseOptions{nil, {Strict: true}, {AllowExtendedWhitespace: true}} {
+			var syntaxErr *SyntaxError
+			if _, err := ParseResponse(value, opts); !errors.As(err, &syntaxErr) {
+				t.Errorf("ParseResponse(%q, %+v) = %v, want a SyntaxError", value, opts, err)
+			}
+		}
+	}
+}
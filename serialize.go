@@ -0,0 +1,313 @@
+package cache
+
+import (
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// String serializes the request directives back into a Cache-Control header
+// value. Unset directives are omitted and extensions are emitted last, in the
+// order they were parsed.
+//
+// max-stale is written as `max-stale=N` when MaxStale is set and as a bare
+// `max-stale` when only MaxStaleAny is, so both forms survive a round trip.
+// Should both be set, the bounded MaxStale wins, as it is the stricter one.
+func (directive *RequestCacheDirective) String() string {
+	return directive.serialize(directive.Extensions)
+}
+
+// StringCanonical is like String but sorts the extensions, so that two
+// semantically identical directives always serialize to the same bytes.
+func (directive *RequestCacheDirective) StringCanonical() string {
+	return directive.serialize(sortedCopy(directive.Extensions))
+}
+
+func (directive *RequestCacheDirective) serialize(extensions []string) string {
+	var w headerWriter
+	w.deltaSeconds(HeaderMaxAge, directive.MaxAge)
+	w.deltaSeconds(HeaderMaxStale, directive.MaxStale)
+	w.flag(HeaderMaxStale, directive.MaxStaleAny && directive.MaxStale < 0)
+	w.deltaSeconds(HeaderMinFresh, directive.MinFresh)
+	w.deltaSeconds(HeaderStaleWhileRevalidate, directive.StaleWhileRevalidate)
+	w.deltaSeconds(HeaderStaleIfError, directive.StaleIfError)
+	w.flag(HeaderNoCache, directive.NoCache)
+	w.flag(HeaderNoStore, directive.NoStore)
+	w.flag(HeaderNoTransform, directive.NoTransform)
+	w.flag(HeaderOnlyIfCached, directive.OnlyIfCached)
+	w.extensions(extensions)
+	return string(w.buf)
+}
+
+// String serializes the response directives back into a Cache-Control header
+// value. Unset directives are omitted, no-cache and private field lists are
+// always written as sorted quoted-strings (RFC 9111 Section 5.2.2.4) and
+// extensions are emitted last, in the order they were parsed.
+//
+// Directives are written in the order used by most CDNs:
+//
+//  1. public, private, no-cache, no-store (who may store the response)
+//  2. max-age, s-maxage (freshness)
+//  3. must-revalidate, proxy-revalidate, no-transform, immutable
+//  4. stale-while-revalidate, stale-if-error
+//  5. extensions
+func (directive *ResponseCacheDirective) String() string {
+	return directive.serialize(directive.Extensions)
+}
+
+// StringCanonical is like String but also sorts the extensions, so that two
+// semantically identical directives always serialize to the same bytes. Use it
+// for snapshot tests or when the header takes part in a cache key.
+func (directive *ResponseCacheDirective) StringCanonical() string {
+	return directive.serialize(sortedCopy(directive.Extensions))
+}
+
+// SharedString is like String but only writes the directives a shared cache
+// acts on, for a CDN that keeps one Cache-Control value for its own storage
+// decisions and forwards another downstream.
+//
+// The filtering rule is:
+//
+//   - immutable is dropped: RFC 8246 only changes how user agents handle a
+//     reload, which never reaches a shared cache as such
+//   - max-age is dropped when s-maxage is set, since s-maxage overrides it for
+//     shared caches (RFC 9111 Section 5.2.2.10)
+//   - everything else, cache-extensions included, is kept, as their meaning
+//     for shared caches is either defined or unknown
+func (directive *ResponseCacheDirective) SharedString() string {
+	var w headerWriter
+	for _, name := range responseDirectives {
+		switch {
+		case name == HeaderImmutable:
+			continue
+		case name == HeaderMaxAge && directive.SMaxAgePresent:
+			continue
+		}
+		directive.writeDirective(&w, name)
+	}
+	w.extensions(directive.Extensions)
+	return string(w.buf)
+}
+
+// responseDirectives lists the response directives in serialization order.
+var responseDirectives = []string{
+	HeaderPublic, HeaderPrivate, HeaderNoCache, HeaderNoStore,
+	HeaderMaxAge, HeaderSMaxAge,
+	HeaderMustRevalidate, HeaderProxyRevalidate, HeaderNoTransform, HeaderImmutable,
+	HeaderStaleWhileRevalidate, HeaderStaleIfError,
+}
+
+// AppendString appends the String form of the directive to dst and returns
+// the extended buffer, avoiding the intermediate string.
+func (directive *ResponseCacheDirective) AppendString(dst []byte) []byte {
+	return directive.appendTo(dst, directive.Extensions)
+}
+
+// WriteTo writes the String form of the directive to w. It implements
+// io.WriterTo.
+func (directive *ResponseCacheDirective) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(directive.AppendString(nil))
+	return int64(n), err
+}
+
+func (directive *ResponseCacheDirective) serialize(extensions []string) string {
+	return string(directive.appendTo(nil, extensions))
+}
+
+func (directive *ResponseCacheDirective) appendTo(dst []byte, extensions []string) []byte {
+	w := headerWriter{buf: dst, start: len(dst)}
+	for _, name := range responseDirectives {
+		directive.writeDirective(&w, name)
+	}
+	w.extensions(extensions)
+	return w.buf
+}
+
+// writeDirective writes the named directive to w, if it is set.
+func (directive *ResponseCacheDirective) writeDirective(w *headerWriter, name string) {
+	switch name {
+	case HeaderPublic:
+		w.flag(HeaderPublic, directive.Public)
+	case HeaderPrivate:
+		w.fieldList(HeaderPrivate, directive.PrivatePresent, directive.Private)
+	case HeaderNoCache:
+		w.fieldList(HeaderNoCache, directive.NoCachePresent, directive.NoCache)
+	case HeaderNoStore:
+		w.flag(HeaderNoStore, directive.NoStore)
+	case HeaderMaxAge:
+		w.deltaSeconds(HeaderMaxAge, directive.maxAge())
+	case HeaderSMaxAge:
+		w.deltaSeconds(HeaderSMaxAge, directive.sMaxAge())
+	case HeaderMustRevalidate:
+		w.flag(HeaderMustRevalidate, directive.MustRevalidate)
+	case HeaderProxyRevalidate:
+		w.flag(HeaderProxyRevalidate, directive.ProxyRevalidate)
+	case HeaderNoTransform:
+		w.flag(HeaderNoTransform, directive.NoTransform)
+	case HeaderImmutable:
+		w.flag(HeaderImmutable, directive.Immutable)
+	case HeaderStaleWhileRevalidate:
+		w.deltaSeconds(HeaderStaleWhileRevalidate, directive.staleWhileRevalidate())
+	case HeaderStaleIfError:
+		w.deltaSeconds(HeaderStaleIfError, directive.staleIfError())
+	}
+}
+
+// fragment returns the serialized form of the named directive, or "" if it
+// is not set.
+func (directive *ResponseCacheDirective) fragment(name string) string {
+	var w headerWriter
+	directive.writeDirective(&w, name)
+	return string(w.buf)
+}
+
+func sortedCopy(s []string) []string {
+	if len(s) < 2 {
+		return s
+	}
+	sorted := make([]string, len(s))
+	copy(sorted, s)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// headerWriter accumulates comma separated directives, appending to buf
+// after its first start bytes.
+type headerWriter struct {
+	buf   []byte
+	start int
+}
+
+func (w *headerWriter) next() {
+	if len(w.buf) > w.start {
+		w.buf = append(w.buf, ", "...)
+	}
+}
+
+func (w *headerWriter) flag(name string, set bool) {
+	if !set {
+		return
+	}
+	w.next()
+	w.buf = append(w.buf, name...)
+}
+
+func (w *headerWriter) deltaSeconds(name string, delta int32) {
+	if delta < 0 {
+		return
+	}
+	w.next()
+	w.buf = append(w.buf, name...)
+	w.buf = append(w.buf, '=')
+	w.buf = strconv.AppendInt(w.buf, int64(delta), 10)
+}
+
+// fieldList writes a no-cache or private directive. The list is always quoted,
+// and appendQuotedString escapes quotes and backslashes in the field names. A
+// name containing ',' would be split by the parser, and control characters
+// other than HTAB are written as '?': neither can come out of parsing, and a
+// hand-built name holding them does not survive a round trip.
+func (w *headerWriter) fieldList(name string, present bool, fields map[string]bool) {
+	if !present {
+		return
+	}
+	w.next()
+	w.buf = append(w.buf, name...)
+	if len(fields) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+
+	list := names[0]
+	for _, field := range names[1:] {
+		list += ", " + field
+	}
+	w.buf = append(w.buf, '=')
+	w.buf = appendQuotedString(w.buf, list)
+}
+
+func (w *headerWriter) extensions(exts []string) {
+	for _, ext := range exts {
+		w.next()
+		w.buf = append(w.buf, quoteExtension(ext)...)
+	}
+}
+
+// quoteExtension returns ext, a raw cache-extension, in a form that parses
+// back to the same value. Extensions coming from the parser already are, but
+// one built by hand such as `reason=not found` would be cut at the space: a
+// value that is neither a token nor a complete quoted-string is quoted.
+//
+// This also covers values the parser accepts unquoted although they are not
+// tokens, such as base64 padding in `sig=abc123==`: it is written as
+// `sig="abc123=="`, the only form RFC 9110 allows, and ExtensionValue returns
+// abc123== for both. A quoted-string holding control characters is quoted
+// again with them substituted, so the header never carries one.
+func quoteExtension(ext string) string {
+	name, val, ok := strings.Cut(ext, "=")
+	if !ok || val == "" || isTokenString(val) {
+		return ext
+	}
+	if val[0] == '"' {
+		if eaten, _, _ := parseQuotedString(val, ErrorOnInvalidQuotedByte); eaten == len(val) {
+			return ext
+		}
+		if eaten, value, _ := parseQuotedString(val, SubstituteInvalidQuotedByte); eaten == len(val) {
+			return name + "=" + string(appendQuotedString(nil, value))
+		}
+	}
+	return formatExtension(name, val)
+}
+
+// formatExtension formats a cache-extension pair, quoting val unless it is a
+// non-empty token.
+func formatExtension(key, val string) string {
+	if isTokenString(val) {
+		return key + "=" + val
+	}
+	return key + "=" + string(appendQuotedString(nil, val))
+}
+
+// appendQuotedString appends s as a quoted-string, escaping quotes and
+// backslashes with a quoted-pair. It is the inverse of parseQuotedString. HTAB
+// is qdtext and written as is; the other control characters cannot be
+// represented, escaped or not, and are written as '?' the way
+// SubstituteInvalidQuotedByte reads them.
+func appendQuotedString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' || c == '\\':
+			buf = append(buf, '\\', c)
+		case !isQuotedPairText(c):
+			buf = append(buf, '?')
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return append(buf, '"')
+}
+
+// Directives returns the serialized directives, extensions included, as a
+// lexicographically sorted slice, e.g. ["immutable", "max-age=60", "public"].
+// Unlike String, which follows a semantic order, it is meant for deterministic
+// logging and snapshot comparisons.
+func (directive *ResponseCacheDirective) Directives() []string {
+	directives := make([]string, 0, len(responseDirectives)+len(directive.Extensions))
+	for _, name := range responseDirectives {
+		if fragment := directive.fragment(name); fragment != "" {
+			directives = append(directives, fragment)
+		}
+	}
+	for _, ext := range directive.Extensions {
+		directives = append(directives, quoteExtension(ext))
+	}
+	sort.Strings(directives)
+	return directives
+}
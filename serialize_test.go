@@ -0,0 +1,289 @@
+package cache
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestQuotedExtensionRoundTrip(t *testing.T) {
+	for _, value := range []string{
+		`ext="a, b"`,
+		`max-age=60, vary-notify="Accept, Accept-Encoding"`,
+		`ext=token`,
+		`ext`,
+	} {
+		directive, err := NewResponseCacheDirective(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := directive.String(); got != value {
+			t.Errorf("%q round-trips to %q", value, got)
+		}
+	}
+}
+
+// failingWriter accepts n bytes, then fails.
+type failingWriter struct{ n int }
+
+var errWriteFailed = errors.New("write failed")
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		return w.n, errWriteFailed
+	}
+	return len(p), nil
+}
+
+func TestWriteTo(t *testing.T) {
+	directive, err := NewResponseCacheDirective(`public, max-age=60, ext="a b"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := directive.String()
+
+	var buf bytes.Buffer
+	n, err := directive.WriteTo(&buf)
+	if err != nil || n != int64(len(want)) || buf.String() != want {
+		t.Errorf("WriteTo(buffer) = %d, %v, wrote %q, want %d, nil, %q", n, err, buf.String(), len(want), want)
+	}
+
+	n, err = directive.WriteTo(&failingWriter{n: 5})
+	if !errors.Is(err, errWriteFailed) || n != 5 {
+		t.Errorf("WriteTo(failing) = %d, %v, want 5, %v", n, err, errWriteFailed)
+	}
+
+	var _ io.WriterTo = directive
+}
+
+func TestAppendString(t *testing.T) {
+	directive, err := NewResponseCacheDirective("no-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(directive.AppendString([]byte("Cache-Control: "))); got != "Cache-Control: no-store" {
+		t.Errorf("AppendString = %q", got)
+	}
+}
+
+func TestEqualsInsideQuotedExtension(t *testing.T) {
+	for _, tt := range []struct {
+		value, want string
+	}{
+		{`ext="a=b"`, "a=b"},
+		{`ext="=", max-age=60`, "="},
+	} {
+		directive, err := NewResponseCacheDirective(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		name := extensionName(directive.Extensions[0])
+		if got, _ := directive.ExtensionValue(name); got != tt.want {
+			t.Errorf("%q: ExtensionValue(%s) = %q, want %q", tt.value, name, got, tt.want)
+		}
+
+		again, err := NewResponseCacheDirective(directive.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := again.ExtensionValue(name); got != tt.want {
+			t.Errorf("%q round-trips through %q to %q", tt.value, directive.String(), got)
+		}
+	}
+}
+
+func TestPathologicalFieldNameRoundTrip(t *testing.T) {
+	for _, field := range []string{`X-"Quoted"`, `X-Back\slash`, "X-Tab\tName", `X-Both\"`, "X-Obs\xe9"} {
+		directive := newResponseCacheDirective()
+		directive.NoCachePresent = true
+		directive.NoCache = map[string]bool{field: true, "Set-Cookie": true}
+
+		value := directive.String()
+		again, err := NewResponseCacheDirective(value)
+		if err != nil {
+			t.Errorf("%q: NewResponseCacheDirective(%q): %v", field, value, err)
+			continue
+		}
+		if !again.NoCache[field] || len(again.NoCache) != 2 {
+			t.Errorf("%q serializes as %q, which parses back to %q", field, value, sortedFields(again.NoCache))
+		}
+	}
+}
+
+func TestAppendQuotedStringInvertsParseQuotedString(t *testing.T) {
+	for _, s := range []string{"", "plain", `"`, `\`, `a\"b`, "tab\there", `\n\t`, "\x80\xff"} {
+		quoted := string(appendQuotedString(nil, s))
+		eaten, value, _ := parseQuotedString(quoted, SubstituteInvalidQuotedByte)
+		if eaten != len(quoted) || value != s {
+			t.Errorf("%q quotes as %s, which unquotes to %q", s, quoted, value)
+		}
+	}
+	if got, want := string(appendQuotedString(nil, "a\tb")), "\"a\tb\""; got != want {
+		t.Errorf("HTAB quotes as %q, want %q", got, want)
+	}
+}
+
+func TestQuotedPairIsTheOctetAfterTheBackslash(t *testing.T) {
+	tests := []struct {
+		raw   string
+		value string
+	}{
+		{`"a\nb"`, "anb"},
+		{`"a\tb"`, "atb"},
+		{`"\r\n"`, "rn"},
+		{`"\a\b\f\v"`, "abfv"},
+		{`"\"\\"`, `"\`},
+	}
+	for _, tt := range tests {
+		eaten, value, _ := parseQuotedString(tt.raw, SubstituteInvalidQuotedByte)
+		if eaten != len(tt.raw) || value != tt.value {
+			t.Errorf("parseQuotedString(%s) = %d, %q, want %d, %q", tt.raw, eaten, value, len(tt.raw), tt.value)
+		}
+	}
+
+	directive, err := ParseStrict(`ext="a\nb"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := directive.ExtensionValue("ext"); value != "anb" {
+		t.Errorf("ExtensionValue(ext) = %q, want %q", value, "anb")
+	}
+}
+
+func TestDirectivesSorted(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"public, max-age=60, immutable", "immutable|max-age=60|public"},
+		{`private="X-Id, Set-Cookie", s-maxage=0, ext="a b", flag`, `ext="a b"|flag|private="Set-Cookie, X-Id"|s-maxage=0`},
+		{"no-store, no-cache, must-revalidate", "must-revalidate|no-cache|no-store"},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Join(directive.Directives(), "|"); got != tt.want {
+			t.Errorf("%q: Directives() = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestHandBuiltExtensionRoundTrip(t *testing.T) {
+	tests := []struct {
+		ext   string
+		value string
+	}{
+		{"reason=not found", "not found"},
+		{"vary-notify=Accept, Accept-Encoding", "Accept, Accept-Encoding"},
+		{`note=say "hi", bye`, `say "hi", bye`},
+		{`path=a\b`, `a\b`},
+		{"token=abc", "abc"},
+	}
+	for _, tt := range tests {
+		directive := &ResponseCacheDirective{MaxAge: 60, MaxAgePresent: true, Extensions: []string{tt.ext}}
+		header := directive.String()
+		parsed, err := NewResponseCacheDirective(header)
+		if err != nil {
+			t.Errorf("%q: NewResponseCacheDirective(%q): %v", tt.ext, header, err)
+			continue
+		}
+		if len(parsed.Extensions) != 1 {
+			t.Errorf("%q: %q parses to Extensions %q, want one", tt.ext, header, parsed.Extensions)
+			continue
+		}
+		name := extensionName(tt.ext)
+		if value, ok := parsed.ExtensionValue(name); !ok || value != tt.value {
+			t.Errorf("%q: %q parses to ExtensionValue(%q) = %q, %v, want %q", tt.ext, header, name, value, ok, tt.value)
+		}
+	}
+}
+
+func TestMaxStaleSerialization(t *testing.T) {
+	tests := []struct {
+		name     string
+		any      bool
+		maxStale int32
+		want     string
+	}{
+		{"unset", false, -1, ""},
+		{"bare", true, -1, "max-stale"},
+		{"valued", false, 60, "max-stale=60"},
+		{"zero", false, 0, "max-stale=0"},
+		{"both", true, 60, "max-stale=60"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			directive := newRequestCacheDirective()
+			directive.MaxStaleAny = tt.any
+			directive.MaxStale = tt.maxStale
+			got := directive.String()
+			if got != tt.want {
+				t.Fatalf("String() = %q, want %q", got, tt.want)
+			}
+
+			parsed, err := NewRequestCacheDirective(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if parsed.String() != got || parsed.MaxStale != tt.maxStale || parsed.MaxStaleAny != (tt.any && tt.maxStale < 0) {
+				t.Errorf("%q parses to MaxStaleAny, MaxStale = %v, %d", got, parsed.MaxStaleAny, parsed.MaxStale)
+			}
+		})
+	}
+}
+
+func TestResponseZeroValue(t *testing.T) {
+	var zero ResponseCacheDirective
+	if got := zero.String(); got != "" {
+		t.Errorf("zero value serializes as %q, want none", got)
+	}
+
+	value := "max-age=0, s-maxage=0, stale-while-revalidate=0, stale-if-error=0"
+	directive, err := NewResponseCacheDirective(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !directive.MaxAgePresent || !directive.SMaxAgePresent || !directive.StaleWhileRevalidatePresent || !directive.StaleIfErrorPresent {
+		t.Errorf("%q: presence flags = %v, %v, %v, %v, want all true", value,
+			directive.MaxAgePresent, directive.SMaxAgePresent, directive.StaleWhileRevalidatePresent, directive.StaleIfErrorPresent)
+	}
+	if got := directive.String(); got != value {
+		t.Errorf("%q serializes as %q", value, got)
+	}
+	if directive.Equal(&zero) {
+		t.Errorf("%q is Equal to the zero value", value)
+	}
+}
+
+func TestControlBytesRoundTrip(t *testing.T) {
+	tests := []struct {
+		directive *ResponseCacheDirective
+		header    string
+	}{
+		{&ResponseCacheDirective{Extensions: []string{"0=\x00"}}, `0="?"`},
+		{&ResponseCacheDirective{Extensions: []string{"ext=a\x01b\x7f"}}, `ext="a?b?"`},
+		{&ResponseCacheDirective{Extensions: []string{"ext=\"a\x1fb\""}}, `ext="a?b"`},
+		{&ResponseCacheDirective{Extensions: []string{"ext=a\tb"}}, "ext=\"a\tb\""},
+		{&ResponseCacheDirective{NoCachePresent: true, NoCache: map[string]bool{"a\x00b": true}}, `no-cache="a?b"`},
+	}
+	for _, tt := range tests {
+		header := tt.directive.String()
+		if header != tt.header {
+			t.Errorf("%q: String() = %q, want %q", tt.directive.Extensions, header, tt.header)
+			continue
+		}
+		parsed, err := NewResponseCacheDirective(header)
+		if err != nil {
+			t.Errorf("NewResponseCacheDirective(%q): %v", header, err)
+			continue
+		}
+		if again := parsed.String(); again != header {
+			t.Errorf("%q parses and serializes as %q", header, again)
+		}
+	}
+}
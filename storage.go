@@ -0,0 +1,116 @@
+package cache
+
+// Uncacheable reports whether a cache is forbidden from storing the response
+// at all. It is the guard-clause form of the storability rules in RFC 9111
+// Section 3 that can be decided from the Cache-Control directives alone:
+//
+//   - no-store is present (RFC 9111 Section 5.2.2.5);
+//   - shared is true and private is present without a field list
+//     (RFC 9111 Section 5.2.2.7). A qualified private=field-list only limits
+//     the listed fields, the rest of the response may still be stored.
+//
+// Conditions that depend on the request method, the status code or request
+// headers such as Authorization are outside the scope of this package and
+// must be checked by the caller.
+func (directive *ResponseCacheDirective) Uncacheable(shared bool) bool {
+	if directive.NoStore {
+		return true
+	}
+	if shared && directive.PrivatePresent && len(directive.Private) == 0 {
+		return true
+	}
+	return false
+}
+
+// PrivateFieldsToStrip returns the canonicalized header field names, sorted,
+// that a shared cache must remove before storing the response, as listed by a
+// qualified private=field-list (RFC 9111 Section 5.2.2.7).
+//
+// It returns nil when private is absent, and also when private is bare: a bare
+// private does not ask for fields to be stripped, it forbids shared storage
+// altogether, which Uncacheable(true) reports.
+func (directive *ResponseCacheDirective) PrivateFieldsToStrip() []string {
+	if len(directive.Private) == 0 {
+		return nil
+	}
+	return sortedFields(directive.Private)
+}
+
+// Storable reports whether a cache of the given type may store the response,
+// following the storability rules of RFC 9111 Section 3 that can be decided
+// from the Cache-Control directives and the status code. It answers whether
+// the response may be kept, not whether a stored response may be reused: see
+// the freshness predicates and EffectivePolicy for that.
+//
+// The response is storable when it is not Uncacheable and at least one of
+// these holds:
+//
+//   - public or max-age is present;
+//   - shared is true and s-maxage is present;
+//   - shared is false and private is present;
+//   - status is heuristically cacheable (RFC 9110 Section 15.1).
+//
+// Pass 0 as status when it is not known. An Expires header field also makes a
+// response storable; as this package only sees Cache-Control, callers must
+// check it themselves.
+func (directive *ResponseCacheDirective) Storable(shared bool, status int) bool {
+	if directive.Uncacheable(shared) {
+		return false
+	}
+
+	switch {
+	case directive.Public, directive.MaxAgePresent:
+		return true
+	case shared && directive.SMaxAgePresent:
+		return true
+	case !shared && directive.PrivatePresent:
+		return true
+	}
+	return heuristicallyCacheable(status)
+}
+
+// heuristicallyCacheable reports whether responses with the given status code
+// are cacheable by default (RFC 9110 Section 15.1).
+func heuristicallyCacheable(status int) bool {
+	switch status {
+	case 200, 203, 204, 206, 300, 301, 308, 404, 405, 410, 414, 501:
+		return true
+	}
+	return false
+}
+
+// BlocksBFCache reports whether browsers are likely to keep the page out of
+// their back/forward cache because of its Cache-Control, which is the case for
+// no-store. This is an advisory heuristic about current browser behaviour, not
+// an RFC 9111 rule: the back/forward cache is not an HTTP cache, browsers are
+// relaxing the restriction, and some also hesitate on no-cache, which is not
+// reported here. It is meant for web-performance tooling that warns about
+// no-store on HTML pages.
+func (directive *ResponseCacheDirective) BlocksBFCache() bool {
+	return directive.NoStore
+}
+
+// QuickStorability reports whether the response Cache-Control value carries
+// no-store and no-cache, for fast paths that need nothing else. It only looks
+// at directive names with a DirectiveScanner, without building a
+// ResponseCacheDirective, and stops as soon as both have been seen.
+//
+// Values are not validated, so a header that NewResponseCacheDirective would
+// reject, e.g. for a bad max-age, still reports its flags. Scanning stops at
+// the first lexical error, and only the directives before it are reported.
+func QuickStorability(value string) (noStore, noCache bool) {
+	s := NewDirectiveScanner(value)
+	for s.Scan() {
+		name, _, _ := s.Directive()
+		switch resolveAlias(name) {
+		case HeaderNoStore:
+			noStore = true
+		case HeaderNoCache:
+			noCache = true
+		}
+		if noStore && noCache {
+			break
+		}
+	}
+	return noStore, noCache
+}
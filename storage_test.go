@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQuickStorability(t *testing.T) {
+	tests := []struct {
+		value            string
+		noStore, noCache bool
+	}{
+		{"", false, false},
+		{"max-age=60, public", false, false},
+		{"no-store", true, false},
+		{"No-Cache", false, true},
+		{`no-cache="Set-Cookie", no-store`, true, true},
+		{"max-age=abc, no-store", true, false},
+		{`ext="no-store", no-cache`, false, true},
+	}
+	for _, tt := range tests {
+		noStore, noCache := QuickStorability(tt.value)
+		if noStore != tt.noStore || noCache != tt.noCache {
+			t.Errorf("QuickStorability(%q) = %v, %v, want %v, %v", tt.value, noStore, noCache, tt.noStore, tt.noCache)
+		}
+	}
+}
+
+const benchmarkStorabilityValue = `public, max-age=3600, s-maxage=86400, stale-while-revalidate=60, ` +
+	`ext-a=1, ext-b="two words", ext-c, no-cache="Set-Cookie"`
+
+func BenchmarkQuickStorability(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		QuickStorability(benchmarkStorabilityValue)
+	}
+}
+
+func BenchmarkStorabilityFullParse(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		directive, err := NewResponseCacheDirective(benchmarkStorabilityValue)
+		if err != nil {
+			b.Fatal(err)
+		}
+		_, _ = directive.NoStore, directive.NoCachePresent
+	}
+}
+
+func TestPrivateFieldsToStrip(t *testing.T) {
+	tests := []struct {
+		value       string
+		fields      []string
+		uncacheable bool
+	}{
+		{"max-age=60", nil, false},
+		{"private", nil, true},
+		{"private=set-cookie", []string{"Set-Cookie"}, false},
+		{`private="x-id, Set-Cookie"`, []string{"Set-Cookie", "X-Id"}, false},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := directive.PrivateFieldsToStrip(); !reflect.DeepEqual(got, tt.fields) {
+			t.Errorf("%q: PrivateFieldsToStrip() = %q, want %q", tt.value, got, tt.fields)
+		}
+		if got := directive.Uncacheable(true); got != tt.uncacheable {
+			t.Errorf("%q: Uncacheable(true) = %v, want %v", tt.value, got, tt.uncacheable)
+		}
+	}
+}
+
+func TestStorable(t *testing.T) {
+	tests := []struct {
+		value   string
+		status  int
+		shared  bool
+		private bool
+	}{
+		{"", 0, false, false},
+		{"", 200, true, true},
+		{"", 201, false, false},
+		{"", 404, true, true},
+		{"public", 500, true, true},
+		{"max-age=0", 201, true, true},
+		{"s-maxage=60", 201, true, false},
+		{"private", 201, false, true},
+		{`private="Set-Cookie"`, 201, false, true},
+		{"private, max-age=60", 200, false, true},
+		{"no-store, public, max-age=60", 200, false, false},
+		{"no-cache", 201, false, false},
+		{"no-cache, max-age=60", 201, true, true},
+	}
+	for _, tt := range tests {
+		directive, err := NewResponseCacheDirective(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := directive.Storable(true, tt.status); got != tt.shared {
+			t.Errorf("%q: Storable(true, %d) = %v, want %v", tt.value, tt.status, got, tt.shared)
+		}
+		if got := directive.Storable(false, tt.status); got != tt.private {
+			t.Errorf("%q: Storable(false, %d) = %v, want %v", tt.value, tt.status, got, tt.private)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrSyntax = errors.New("invalid Cache-Control syntax")
+)
+
+// SyntaxError reports where a Cache-Control value deviates from the RFC 9110
+// grammar. It unwraps to ErrSyntax.
+type SyntaxError struct {
+	// Offset is the byte offset of the offending character.
+	Offset int
+
+	// Msg describes what was expected.
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%v at offset %d: %s", ErrSyntax, e.Offset, e.Msg)
+}
+
+func (e *SyntaxError) Unwrap() error { return ErrSyntax }
+
+// ParseStrict parses a response Cache-Control value, rejecting anything that
+// does not match the grammar of RFC 9111 Section 5.2 exactly:
+//
+//	Cache-Control   = #cache-directive
+//	cache-directive = token [ "=" ( token / quoted-string ) ]
+//
+// List elements must be separated by OWS "," OWS with no empty elements, no
+// whitespace is allowed around "=", quoted-strings may only contain qdtext and
+// quoted-pair, and nothing may follow the last directive but OWS.
+// Use ParseRequest with ParseOptions.Strict for request values.
+func ParseStrict(value string) (*ResponseCacheDirective, error) {
+	return ParseResponse(value, &ParseOptions{Strict: true})
+}
+
+// validateGrammar checks val against the sender grammar of #cache-directive
+// (RFC 9110 Section 5.6.1 and RFC 9111 Section 5.2).
+func validateGrammar(val string) error {
+	var (
+		index = 0
+		vl    = len(val)
+	)
+
+	skipOWS := func() {
+		for index < vl && isWhiteSpace(val[index]) {
+			index++
+		}
+	}
+
+	skipOWS()
+	if index == vl {
+		return nil
+	}
+
+	for {
+		nameStart := index
+		for index < vl && isToken(val[index]) {
+			index++
+		}
+		if index == nameStart {
+			return &SyntaxError{Offset: index, Msg: "expected directive name"}
+		}
+
+		if index < vl && val[index] == '=' {
+			index++
+			if index < vl && val[index] == '"' {
+				end, err := validateQuotedString(val, index)
+				if err != nil {
+					return err
+				}
+				index = end
+			} else {
+				valueStart := index
+				for index < vl && isToken(val[index]) {
+					index++
+				}
+				if index == valueStart {
+					return &SyntaxError{Offset: index, Msg: "expected token or quoted-string after '='"}
+				}
+			}
+		}
+
+		skipOWS()
+		if index == vl {
+			return nil
+		}
+		if val[index] != ',' {
+			return &SyntaxError{Offset: index, Msg: fmt.Sprintf("unexpected character %q, expected ','", val[index])}
+		}
+		index++
+		skipOWS()
+	}
+}
+
+// validateQuotedString checks the quoted-string starting at val[start] and
+// returns the offset just past its closing quote.
+func validateQuotedString(val string, start int) (int, error) {
+	for i := start + 1; i < len(val); i++ {
+		switch c := val[i]; {
+		case c == '"':
+			return i + 1, nil
+		case c == '\\':
+			if i+1 == len(val) || !isQuotedPairText(val[i+1]) {
+				return -1, &SyntaxError{Offset: i, Msg: "invalid quoted-pair"}
+			}
+			i++
+		case !isQdText(c):
+			return -1, &SyntaxError{Offset: i, Msg: fmt.Sprintf("invalid character %q in quoted-string", c)}
+		}
+	}
+	return -1, &SyntaxError{Offset: start, Msg: ErrMissingClosingQuote.Error()}
+}
@@ -0,0 +1,13 @@
+package cache
+
+// AllowsTransform reports whether intermediaries may transform the response
+// content, e.g. recompress images (RFC 9111 Section 5.2.2.6).
+func (directive *ResponseCacheDirective) AllowsTransform() bool {
+	return !directive.NoTransform
+}
+
+// AllowsTransform reports whether intermediaries may transform the request
+// content (RFC 9111 Section 5.2.1.6).
+func (directive *RequestCacheDirective) AllowsTransform() bool {
+	return !directive.NoTransform
+}
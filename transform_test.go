@@ -0,0 +1,32 @@
+package cache
+
+import "testing"
+
+func TestAllowsTransform(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"", true},
+		{"max-age=60", true},
+		{"no-transform", false},
+		{"No-Transform, max-age=60", false},
+	}
+	for _, tt := range tests {
+		req, err := NewRequestCacheDirective(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := req.AllowsTransform(); got != tt.want {
+			t.Errorf("request %q: AllowsTransform() = %v, want %v", tt.value, got, tt.want)
+		}
+
+		resp, err := NewResponseCacheDirective(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := resp.AllowsTransform(); got != tt.want {
+			t.Errorf("response %q: AllowsTransform() = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package cache
+
+import "errors"
+
+var (
+	ErrImmutableWithoutMaxAge  = errors.New("immutable directive has no effect without a non-zero `max-age`")
+	ErrNoCacheBareAndFieldList = errors.New("no-cache directive sent both bare and with a field list")
+	ErrPrivateBareAndFieldList = errors.New("private directive sent both bare and with a field list")
+
+	ErrOnlyIfCachedWithNoCache = errors.New("only-if-cached and no-cache request directives cannot both be satisfied")
+	ErrOnlyIfCachedWithNoStore = errors.New("only-if-cached and no-store request directives contradict each other")
+	ErrMaxStaleWithMinFresh    = errors.New("max-stale and min-fresh request directives pull in opposite directions")
+)
+
+// Validate reports directive combinations that are syntactically valid but
+// meaningless or contradictory. A nil result means no problems were found.
+//
+//   - immutable without a non-zero max-age: RFC 8246 Section 2 only defines
+//     immutable for the freshness lifetime, so there is nothing to skip.
+//   - no-cache or private both bare and with a field list, as in
+//     `no-cache, no-cache="Set-Cookie"`, in either order: RFC 9111 does not
+//     say which applies. The parser lets the bare form win, as it covers every
+//     field including the listed ones and so is never less safe to honour.
+//     The listed names are kept in NoCacheRaw or PrivateRaw only.
+func (directive *ResponseCacheDirective) Validate() []error {
+	var errs []error
+	if directive.Immutable && directive.MaxAge <= 0 {
+		errs = append(errs, ErrImmutableWithoutMaxAge)
+	}
+	if directive.NoCachePresent && len(directive.NoCache) == 0 && len(directive.NoCacheRaw) > 0 {
+		errs = append(errs, ErrNoCacheBareAndFieldList)
+	}
+	if directive.PrivatePresent && len(directive.Private) == 0 && len(directive.PrivateRaw) > 0 {
+		errs = append(errs, ErrPrivateBareAndFieldList)
+	}
+	return errs
+}
+
+// Validate reports request directive combinations a cache cannot satisfy or
+// that are very likely mistakes. A nil result means no problems were found.
+//
+//   - only-if-cached with no-cache: the client refuses to contact the origin
+//     (RFC 9111 Section 5.2.1.7) but also refuses a stored response that was
+//     not revalidated with the origin (Section 5.2.1.4), so every cache must
+//     answer 504 (Gateway Timeout).
+//   - only-if-cached with no-store: the client only wants a stored response
+//     while asking that nothing about the exchange be stored
+//     (Section 5.2.1.5). A cache may still answer it, but the preferences
+//     are contradictory.
+//   - max-stale with min-fresh: one accepts stale responses, the other
+//     demands responses that stay fresh for a while (Sections 5.2.1.2 and
+//     5.2.1.3). min-fresh always wins, so max-stale is dead weight.
+func (directive *RequestCacheDirective) Validate() []error {
+	var errs []error
+	if directive.OnlyIfCached && directive.NoCache {
+		errs = append(errs, ErrOnlyIfCachedWithNoCache)
+	}
+	if directive.OnlyIfCached && directive.NoStore {
+		errs = append(errs, ErrOnlyIfCachedWithNoStore)
+	}
+	if (directive.MaxStale >= 0 || directive.MaxStaleAny) && directive.MinFresh >= 0 {
+		errs = append(errs, ErrMaxStaleWithMinFresh)
+	}
+	return errs
+}
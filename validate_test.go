@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBareAndQualifiedFieldList(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		err   error
+	}{
+		{"no-cache bare then valued", `no-cache, no-cache="Set-Cookie"`, ErrNoCacheBareAndFieldList},
+		{"no-cache valued then bare", `no-cache="Set-Cookie", no-cache`, ErrNoCacheBareAndFieldList},
+		{"private bare then valued", `private, private="Set-Cookie"`, ErrPrivateBareAndFieldList},
+		{"private valued then bare", `private="Set-Cookie", private`, ErrPrivateBareAndFieldList},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			directive, err := NewResponseCacheDirective(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !directive.NoCacheContains("Authorization") && !directive.PrivateContains("Authorization") {
+				t.Errorf("%q: the bare directive does not cover every field", tt.value)
+			}
+			if len(directive.NoCacheRaw)+len(directive.PrivateRaw) != 1 {
+				t.Errorf("%q: raw field names = %q, %q, want Set-Cookie", tt.value, directive.NoCacheRaw, directive.PrivateRaw)
+			}
+
+			errs := directive.Validate()
+			if len(errs) != 1 || !errors.Is(errs[0], tt.err) {
+				t.Errorf("%q: Validate() = %v, want %v", tt.value, errs, tt.err)
+			}
+		})
+	}
+}
+
+func TestQualifiedFieldListIsValid(t *testing.T) {
+	directive, err := NewResponseCacheDirective(`no-cache="Set-Cookie", no-cache="Authorization", private="X-Id"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := directive.Validate(); errs != nil {
+		t.Errorf("Validate() = %v, want nil", errs)
+	}
+	if directive.NoCacheContains("X-Other") {
+		t.Error("a qualified no-cache covers an unlisted field")
+	}
+}
@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// ParseVary parses a Vary header field value (RFC 9110 Section 12.5.5) into
+// its list of request header field names, canonicalized with
+// http.CanonicalHeaderKey and without duplicates, in the order they appear.
+// A "*" member, which means the response varies on more than request headers,
+// is returned as "*". Empty members are ignored.
+func ParseVary(value string) []string {
+	var fields []string
+	for _, member := range strings.Split(value, ",") {
+		member = textproto.TrimString(member)
+		if member == "" {
+			continue
+		}
+		if member != "*" {
+			member = http.CanonicalHeaderKey(member)
+		}
+		if !containsString(fields, member) {
+			fields = append(fields, member)
+		}
+	}
+	return fields
+}
+
+// CanShareCacheEntry reports whether a response stored for reqA, whose Vary
+// header lists the vary fields (see ParseVary), may also be used for reqB: the
+// request headers it nominates must match (RFC 9111 Section 4.1). This is the
+// secondary cache key check; whether the response may be stored and reused at
+// all is decided by the Cache-Control directives.
+//
+// Header values are compared after combining multiple lines and removing the
+// whitespace around commas, a header absent from both requests matches, and a
+// "*" member never matches.
+func CanShareCacheEntry(reqA, reqB http.Header, vary []string) bool {
+	for _, field := range vary {
+		if field == "*" {
+			return false
+		}
+		if varyValue(reqA, field) != varyValue(reqB, field) {
+			return false
+		}
+	}
+	return true
+}
+
+// varyValue returns the values of the named header combined into a single
+// list, without whitespace around the commas, or "\x00" when it is absent so
+// that an absent header does not match an empty one.
+func varyValue(h http.Header, name string) string {
+	values := h.Values(name)
+	if len(values) == 0 {
+		return "\x00"
+	}
+
+	var members []string
+	for _, value := range values {
+		for _, member := range strings.Split(value, ",") {
+			members = append(members, textproto.TrimString(member))
+		}
+	}
+	return strings.Join(members, ",")
+}
+
+// CacheableWithoutVary reports whether the Cache-Control directives let a
+// shared cache store the response and reuse it for any requester, that is
+// Storable(true, 0): it is not private or no-store, and public, max-age or
+// s-maxage is present. It is the fast path for caches that skip secondary key
+// computation for obviously shareable responses.
+//
+// Cache-Control and Vary are independent: Cache-Control decides whether the
+// response may be stored and reused at all, Vary which requests it may be
+// reused for (RFC 9111 Section 4.1). The response only matches every request
+// when, in addition, Vary is absent or empty; a Vary listing header fields
+// requires CanShareCacheEntry, and "*" never matches:
+//
+//	if resp.CacheableWithoutVary() && len(ParseVary(h.Get("Vary"))) == 0 {
+//		// one entry serves every request
+//	}
+func (directive *ResponseCacheDirective) CacheableWithoutVary() bool {
+	return directive.Storable(true, 0)
+}
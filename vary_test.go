@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestParseVary(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"", nil},
+		{" , ,", nil},
+		{"*", []string{"*"}},
+		{"accept-encoding", []string{"Accept-Encoding"}},
+		{"Accept-Encoding, accept-encoding,ACCEPT-ENCODING", []string{"Accept-Encoding"}},
+		{"accept-language ,\tuser-agent, Accept-Language", []string{"Accept-Language", "User-Agent"}},
+		{"Origin, *, origin", []string{"Origin", "*"}},
+	}
+	for _, tt := range tests {
+		if got := ParseVary(tt.value); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseVary(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestCanShareCacheEntry(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b http.Header
+		vary string
+		want bool
+	}{
+		{"no vary", http.Header{"Accept-Encoding": {"gzip"}}, http.Header{}, "", true},
+		{"star", http.Header{}, http.Header{}, "*", false},
+		{"star among fields", http.Header{}, http.Header{}, "Origin, *", false},
+		{"same value", http.Header{"Accept-Encoding": {"gzip"}}, http.Header{"Accept-Encoding": {"gzip"}}, "accept-encoding", true},
+		{"different value", http.Header{"Accept-Encoding": {"gzip"}}, http.Header{"Accept-Encoding": {"br"}}, "Accept-Encoding", false},
+		{"case-folded field name", http.Header{"Accept-Encoding": {"gzip"}}, http.Header{"Accept-Encoding": {"gzip"}}, "ACCEPT-ENCODING", true},
+		{"duplicate field", http.Header{"Origin": {"a"}}, http.Header{"Origin": {"b"}}, "origin, Origin", false},
+		{"absent from both", http.Header{}, http.Header{}, "Origin", true},
+		{"absent from one", http.Header{"Origin": {""}}, http.Header{}, "Origin", false},
+		{"combined lines", http.Header{"Accept": {"a", "b"}}, http.Header{"Accept": {"a ,  b"}}, "Accept", true},
+		{"list order", http.Header{"Accept": {"a, b"}}, http.Header{"Accept": {"b, a"}}, "Accept", false},
+	}
+	for _, tt := range tests {
+		if got := CanShareCacheEntry(tt.a, tt.b, ParseVary(tt.vary)); got != tt.want {
+			t.Errorf("%s: CanShareCacheEntry(%v, %v, %q) = %v, want %v", tt.name, tt.a, tt.b, tt.vary, got, tt.want)
+		}
+	}
+}
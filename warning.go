@@ -0,0 +1,122 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var ErrInvalidWarning = errors.New("invalid Warning header value")
+
+// Warning codes defined by RFC 7234 Section 5.5.
+const (
+	WarnResponseIsStale         = 110
+	WarnRevalidationFailed      = 111
+	WarnDisconnectedOperation   = 112
+	WarnHeuristicExpiration     = 113
+	WarnMiscellaneous           = 199
+	WarnTransformationApplied   = 214
+	WarnMiscellaneousPersistent = 299
+)
+
+// WarningValue is one warning-value of a Warning header field.
+type WarningValue struct {
+	// Code is the three-digit warn-code, e.g. WarnResponseIsStale.
+	Code int
+
+	// Agent is the warn-agent: the host, with an optional port, or the
+	// pseudonym of the server adding the warning, "-" when unknown.
+	Agent string
+
+	// Text is the unquoted warn-text.
+	Text string
+
+	// Date is the optional warn-date, the zero time when absent.
+	Date time.Time
+}
+
+// ParseWarning parses a Warning header field value (RFC 7234 Section 5.5):
+//
+//	Warning       = 1#warning-value
+//	warning-value = warn-code SP warn-agent SP warn-text [ SP warn-date ]
+//
+// The Warning header field is obsolete: RFC 9111 removed it, as it was not
+// widely implemented, and new code should not generate it. It is still found
+// on responses from older caches, where 110 (Response is Stale) and 111
+// (Revalidation Failed) mark stale content, so this package can read it.
+// Malformed values fail with an error wrapping ErrInvalidWarning.
+func ParseWarning(value string) ([]WarningValue, error) {
+	var (
+		warnings []WarningValue
+		index    = 0
+		vl       = len(value)
+	)
+
+	for {
+		for index < vl && (isWhiteSpace(value[index]) || value[index] == ',') {
+			index++
+		}
+		if index == vl {
+			return warnings, nil
+		}
+
+		var w WarningValue
+		if index+4 > vl || !isDigits(value[index:index+3]) || value[index+3] != ' ' {
+			return nil, fmt.Errorf("%w: expected a three-digit warn-code at offset %d", ErrInvalidWarning, index)
+		}
+		w.Code = int(value[index]-'0')*100 + int(value[index+1]-'0')*10 + int(value[index+2]-'0')
+		index += 4
+
+		agentStart := index
+		for index < vl && !isWhiteSpace(value[index]) && value[index] != ',' && !isCtl(value[index]) {
+			index++
+		}
+		if index == agentStart || index == vl || value[index] != ' ' {
+			return nil, fmt.Errorf("%w: expected a warn-agent at offset %d", ErrInvalidWarning, agentStart)
+		}
+		w.Agent = value[agentStart:index]
+		index++
+
+		if index == vl || value[index] != '"' {
+			return nil, fmt.Errorf("%w: expected a quoted warn-text at offset %d", ErrInvalidWarning, index)
+		}
+		eaten, text, _ := parseQuotedString(value[index:], SubstituteInvalidQuotedByte)
+		if eaten == -1 {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidWarning, ErrMissingClosingQuote)
+		}
+		w.Text = text
+		index += eaten
+
+		if index+1 < vl && value[index] == ' ' && value[index+1] == '"' {
+			eaten, date, _ := parseQuotedString(value[index+1:], SubstituteInvalidQuotedByte)
+			if eaten == -1 {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidWarning, ErrMissingClosingQuote)
+			}
+			t, err := http.ParseTime(date)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid warn-date %q", ErrInvalidWarning, date)
+			}
+			w.Date = t
+			index += 1 + eaten
+		}
+
+		for index < vl && isWhiteSpace(value[index]) {
+			index++
+		}
+		if index < vl && value[index] != ',' {
+			return nil, fmt.Errorf("%w: unexpected character %q at offset %d, expected ','", ErrInvalidWarning, value[index], index)
+		}
+		warnings = append(warnings, w)
+	}
+}
+
+// isDigits reports whether s only consists of decimal digits.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
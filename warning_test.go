@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseWarning(t *testing.T) {
+	date := time.Date(2015, time.October, 21, 7, 28, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  []WarningValue
+	}{
+		{"", nil},
+		{`110 - "Response is Stale"`, []WarningValue{{Code: 110, Agent: "-", Text: "Response is Stale"}}},
+		{
+			`111 cache.example.com:8080 "Revalidation Failed" "Wed, 21 Oct 2015 07:28:00 GMT"`,
+			[]WarningValue{{Code: 111, Agent: "cache.example.com:8080", Text: "Revalidation Failed", Date: date}},
+		},
+		{
+			`110 a "stale", 214 b "transformed"`,
+			[]WarningValue{{Code: 110, Agent: "a", Text: "stale"}, {Code: 214, Agent: "b", Text: "transformed"}},
+		},
+		{`, 199 - "misc" ,`, []WarningValue{{Code: 199, Agent: "-", Text: "misc"}}},
+		{`299 - "say \"hi\" \\ bye"`, []WarningValue{{Code: 299, Agent: "-", Text: `say "hi" \ bye`}}},
+		{`199 - "a, b"`, []WarningValue{{Code: 199, Agent: "-", Text: "a, b"}}},
+	}
+	for _, tt := range tests {
+		got, err := ParseWarning(tt.value)
+		if err != nil {
+			t.Errorf("ParseWarning(%q): %v", tt.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseWarning(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseWarningInvalid(t *testing.T) {
+	for _, value := range []string{
+		"110",
+		`11 - "short code"`,
+		`1100 - "long code"`,
+		`abc - "letters"`,
+		`110  "missing agent"`,
+		`110 -`,
+		`110 - stale`,
+		`110 - "unterminated`,
+		`110 - "stale" "Wed, 21 Oct 2015`,
+		`110 - "stale" "not a date"`,
+		`110 - "stale" extra`,
+		`110 - "stale"; 111 - "failed"`,
+	} {
+		if got, err := ParseWarning(value); !errors.Is(err, ErrInvalidWarning) {
+			t.Errorf("ParseWarning(%q) = %+v, %v, want ErrInvalidWarning", value, got, err)
+		}
+	}
+}